@@ -0,0 +1,67 @@
+package database
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes lists the indexes each collection needs, keyed by collection name
+var indexes = map[string][]mongo.IndexModel{
+    "organizations": {
+        // Soft-deleted organizations keep their slug reserved
+        {Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+        {Keys: bson.D{{Key: "previous_slugs", Value: 1}}},
+    },
+    "services": {
+        {Keys: bson.D{{Key: "heartbeat_token_hash", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+    },
+    "status_history": {
+        {Keys: bson.D{{Key: "service_id", Value: 1}, {Key: "created_at", Value: -1}}},
+    },
+    "latency_samples": {
+        {Keys: bson.D{{Key: "service_id", Value: 1}, {Key: "created_at", Value: 1}}},
+        // Samples expire after 30 days
+        {Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60)},
+    },
+    "daily_status": {
+        {Keys: bson.D{{Key: "service_id", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetUnique(true)},
+        {Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "date", Value: 1}}},
+    },
+    "incidents": {
+        // Newest-first paging on the public status page
+        {Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+    },
+    "incident_templates": {
+        {Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "name", Value: 1}}},
+    },
+    "maintenances": {
+        {Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_start", Value: 1}}},
+        {Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_end", Value: 1}}},
+        {Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "scheduled_start", Value: -1}}},
+        // One window per occurrence of a recurring series
+        {Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence_start", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+    },
+    "maintenance_series": {
+        {Keys: bson.D{{Key: "organization_id", Value: 1}}},
+    },
+}
+
+// EnsureIndexes creates any missing indexes. Existing indexes are left alone.
+func EnsureIndexes() error {
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+
+    for name, models := range indexes {
+        if _, err := GetCollection(name).Indexes().CreateMany(ctx, models); err != nil {
+            return err
+        }
+    }
+
+    log.Println("Database indexes are up to date")
+    return nil
+}
\ No newline at end of file
@@ -5,8 +5,10 @@ go 1.24.3
 require (
 	github.com/gin-contrib/cors v1.4.0
 	github.com/gin-gonic/gin v1.9.1
+	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.4.0
+	github.com/teambition/rrule-go v1.8.2
 	go.mongodb.org/mongo-driver v1.12.1
 )
 
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/rollup"
+    "status-page-backend/uptime"
+)
+
+// rollupWindows maps the standard uptime windows onto whole days of rollups
+var rollupWindows = map[string]int{
+    "day":   1,
+    "week":  7,
+    "month": 30,
+    "90d":   90,
+}
+
+// findDailyStatuses returns the organization's rollups for the last days
+// UTC days, oldest first, grouped by service
+func findDailyStatuses(orgID primitive.ObjectID, days int, now time.Time) (map[primitive.ObjectID][]models.DailyStatus, error) {
+    since := now.UTC().Truncate(24 * time.Hour).AddDate(0, 0, -(days - 1))
+
+    cursor, err := database.GetCollection("daily_status").Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "date":            bson.M{"$gte": since},
+        },
+        options.Find().SetSort(bson.D{{Key: "date", Value: 1}}),
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    var statuses []models.DailyStatus
+    if err := cursor.All(context.TODO(), &statuses); err != nil {
+        return nil, err
+    }
+
+    byService := make(map[primitive.ObjectID][]models.DailyStatus)
+    for _, status := range statuses {
+        byService[status.ServiceID] = append(byService[status.ServiceID], status)
+    }
+    return byService, nil
+}
+
+// uptimeFromRollups computes the standard uptime windows from a service's
+// daily rollups, oldest first
+func uptimeFromRollups(days []models.DailyStatus) map[string]float64 {
+    percentages := make(map[string]float64, len(rollupWindows))
+    for name, n := range rollupWindows {
+        first := len(days) - n
+        if first < 0 {
+            first = 0
+        }
+
+        seconds := make(map[models.ServiceStatus]float64)
+        for _, d := range days[first:] {
+            for status, minutes := range d.Minutes {
+                seconds[status] += minutes * 60
+            }
+        }
+        percentages[name], _ = uptime.Percentage(seconds, uptime.DefaultWeights)
+    }
+    return percentages
+}
+
+func GetPublicStatusHistory(c *gin.Context) {
+    days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(rollup.MaxDays)))
+    if err != nil || days < 1 || days > rollup.MaxDays {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and " + strconv.Itoa(rollup.MaxDays)})
+        return
+    }
+
+    org, ok := findPublicOrganization(c)
+    if !ok {
+        return
+    }
+
+    servicesCollection := database.GetCollection("services")
+    cursor, err := servicesCollection.Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": org.ID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        serviceOrder(),
+    )
+    if err != nil {
+        log.Printf("Error finding services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var services []models.Service
+    if err := cursor.All(context.TODO(), &services); err != nil {
+        log.Printf("Error decoding services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode services"})
+        return
+    }
+
+    dailyStatuses, err := findDailyStatuses(org.ID, days, time.Now())
+    if err != nil {
+        log.Printf("Error finding daily statuses: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+        return
+    }
+
+    type serviceHistory struct {
+        ServiceID primitive.ObjectID   `json:"service_id"`
+        Name      string               `json:"name"`
+        Days      []models.DailyStatus `json:"days"`
+    }
+
+    history := make([]serviceHistory, 0, len(services))
+    for _, service := range services {
+        serviceDays := dailyStatuses[service.ID]
+        if serviceDays == nil {
+            serviceDays = make([]models.DailyStatus, 0)
+        }
+        history = append(history, serviceHistory{
+            ServiceID: service.ID,
+            Name:      service.Name,
+            Days:      serviceDays,
+        })
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "organization": org.Slug,
+        "days":         days,
+        "services":     history,
+    })
+}
\ No newline at end of file
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base64"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// CreateHeartbeatToken issues a new secret heartbeat URL for a service,
+// replacing any previous one. The token is only shown once.
+func CreateHeartbeatToken(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    b := make([]byte, 24)
+    if _, err := rand.Read(b); err != nil {
+        log.Printf("Error generating heartbeat token: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+        return
+    }
+    token := base64.RawURLEncoding.EncodeToString(b)
+
+    result, err := database.GetCollection("services").UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "heartbeat_token_hash":     hashToken(token),
+                "heartbeat_expected_since": time.Now(),
+                "updated_at":               time.Now(),
+            },
+        },
+    )
+    if err != nil {
+        log.Printf("Error saving heartbeat token: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save token"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "token":         token,
+        "heartbeat_url": "/api/heartbeat/" + token,
+    })
+}
+
+// ReceiveHeartbeat records a ping from a push monitor. The token in the path
+// is the only credential. If the heartbeat monitor had taken the service down
+// it recovers right away.
+func ReceiveHeartbeat(c *gin.Context) {
+    token := c.Param("token")
+    now := time.Now()
+
+    collection := database.GetCollection("services")
+    var service models.Service
+    err := collection.FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "heartbeat_token_hash": hashToken(token),
+            "deleted":              bson.M{"$ne": true},
+        },
+        bson.M{"$set": bson.M{"last_heartbeat_at": now}},
+        options.FindOneAndUpdate().SetReturnDocument(options.Before),
+    ).Decode(&service)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Unknown heartbeat token"})
+        } else {
+            log.Printf("Error recording heartbeat: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    isHeartbeat := isHeartbeatMonitor(service.Monitor)
+    state := service.MonitorState
+    if isHeartbeat && state != nil && state.AppliedStatus != "" && service.Status == state.AppliedStatus {
+        // Only undo the monitor's own status, not one set since
+        _, err := ReplaceServiceStatus(websocketHub(c), service.OrganizationID, service.ID, state.AppliedStatus, models.StatusOperational, "Heartbeat received", models.ActorMonitor)
+        if err == mongo.ErrNoDocuments {
+            log.Printf("Status of %s changed since the monitor set it, leaving it", service.Name)
+        } else if err != nil {
+            log.Printf("Error recovering %s after heartbeat: %v", service.Name, err)
+        } else {
+            _, err = collection.UpdateOne(
+                context.TODO(),
+                bson.M{"_id": service.ID},
+                bson.M{"$set": bson.M{
+                    "monitor_state.applied_status":       "",
+                    "monitor_state.healthy":              true,
+                    "monitor_state.consecutive_failures": 0,
+                    "monitor_state.last_message":         "heartbeat received",
+                }},
+            )
+            if err != nil {
+                log.Printf("Error saving monitor state for %s: %v", service.Name, err)
+            }
+        }
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Heartbeat received"})
+}
\ No newline at end of file
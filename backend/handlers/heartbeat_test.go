@@ -0,0 +1,61 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestReceiveHeartbeatKeepsStatusSetSinceTheMonitor(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "Nightly backup")
+    monitor := models.MonitorConfig{Enabled: true, Type: models.MonitorHeartbeat, PeriodSeconds: 300}
+    monitor.ApplyDefaults()
+    _, err := database.GetCollection("services").UpdateOne(context.TODO(),
+        bson.M{"_id": service.ID},
+        bson.M{"$set": bson.M{
+            "status":               models.StatusMajorOutage,
+            "heartbeat_token_hash": hashToken("secret"),
+            "monitor":              monitor,
+            "monitor_state":        models.MonitorState{AppliedStatus: models.StatusMajorOutage},
+        }},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.POST("/heartbeat/:token", ReceiveHeartbeat)
+
+    // Someone took over the status after the monitor set it
+    if _, err := ApplyServiceStatus(nil, orgID, service.ID, models.StatusPartialOutage, "", "test-user"); err != nil {
+        t.Fatal(err)
+    }
+    if w := doJSON(r, http.MethodPost, "/heartbeat/secret", nil); w.Code != http.StatusOK {
+        t.Fatalf("heartbeat = %d: %s", w.Code, w.Body)
+    }
+    if got := findService(t, service.ID).Status; got != models.StatusPartialOutage {
+        t.Errorf("status = %s, want the user's partial_outage kept", got)
+    }
+
+    // Still the monitor's own status, the ping recovers it
+    if _, err := ApplyServiceStatus(nil, orgID, service.ID, models.StatusMajorOutage, "", models.ActorMonitor); err != nil {
+        t.Fatal(err)
+    }
+    if w := doJSON(r, http.MethodPost, "/heartbeat/secret", nil); w.Code != http.StatusOK {
+        t.Fatalf("heartbeat = %d: %s", w.Code, w.Body)
+    }
+    recovered := findService(t, service.ID)
+    if recovered.Status != models.StatusOperational || recovered.MonitorState.AppliedStatus != "" {
+        t.Errorf("status = %s, applied %q, want operational and handed back", recovered.Status, recovered.MonitorState.AppliedStatus)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+    "context"
+    "errors"
+    "log"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+// validateIncidentImpact checks an incident's impact and status targets, and
+// adds every targeted service to AffectedServices
+func validateIncidentImpact(incident *models.Incident) error {
+    if incident.Impact == "" {
+        incident.Impact = models.ImpactNone
+    }
+    if !incident.Impact.Valid() {
+        return errors.New("impact must be one of none, minor, major or critical")
+    }
+
+    seen := make(map[primitive.ObjectID]bool, len(incident.ServiceStatuses))
+    for _, target := range incident.ServiceStatuses {
+        if !target.Status.Valid() {
+            return errors.New("invalid status for service " + target.ServiceID.Hex())
+        }
+        if seen[target.ServiceID] {
+            return errors.New("service " + target.ServiceID.Hex() + " has more than one status")
+        }
+        seen[target.ServiceID] = true
+    }
+
+    for _, id := range incident.AffectedServices {
+        delete(seen, id)
+    }
+    for _, target := range incident.ServiceStatuses {
+        if seen[target.ServiceID] {
+            incident.AffectedServices = append(incident.AffectedServices, target.ServiceID)
+        }
+    }
+
+    return nil
+}
+
+// servicesBelongTo reports whether every ID is a live service of the organization
+func servicesBelongTo(orgID primitive.ObjectID, ids []primitive.ObjectID) (bool, error) {
+    unique := make(map[primitive.ObjectID]bool, len(ids))
+    for _, id := range ids {
+        unique[id] = true
+    }
+    if len(unique) == 0 {
+        return true, nil
+    }
+
+    count, err := database.GetCollection("services").CountDocuments(context.TODO(), bson.M{
+        "_id":             bson.M{"$in": ids},
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    })
+    if err != nil {
+        return false, err
+    }
+    return count == int64(len(unique)), nil
+}
+
+// syncIncidentImpact moves an incident's services to their target statuses,
+// and back to where they were once it resolves or a service is dropped from
+// it. before is the stored incident prior to the change, zero for a new one.
+// It returns the targets with the statuses they replaced, to be stored.
+func syncIncidentImpact(hub *websocket.Hub, before, after models.Incident, actor string) []models.ServiceImpact {
+    // Targets that are currently in effect
+    applied := make(map[primitive.ObjectID]models.ServiceImpact)
+    if !before.ID.IsZero() && before.Status != models.IncidentStatusResolved {
+        for _, target := range before.ServiceStatuses {
+            applied[target.ServiceID] = target
+        }
+    }
+
+    if after.Status == models.IncidentStatusResolved {
+        for _, target := range applied {
+            restoreServiceStatus(hub, after, target, actor)
+        }
+        // Keep what was applied as a record of the incident's impact
+        if len(applied) > 0 {
+            return before.ServiceStatuses
+        }
+        return after.ServiceStatuses
+    }
+
+    message := "Incident: " + after.Title
+    targets := make([]models.ServiceImpact, 0, len(after.ServiceStatuses))
+    for _, target := range after.ServiceStatuses {
+        if current, ok := applied[target.ServiceID]; ok {
+            delete(applied, target.ServiceID)
+            target.PreviousStatus = current.PreviousStatus
+            if current.Status != target.Status {
+                _, err := ReplaceServiceStatus(hub, after.OrganizationID, target.ServiceID, current.Status, target.Status, message, actor)
+                if err != nil && err != mongo.ErrNoDocuments {
+                    log.Printf("Error applying incident status to %s: %v", target.ServiceID.Hex(), err)
+                }
+            }
+            targets = append(targets, target)
+            continue
+        }
+
+        service, err := ApplyServiceStatus(hub, after.OrganizationID, target.ServiceID, target.Status, message, actor)
+        if err != nil {
+            log.Printf("Error applying incident status to %s: %v", target.ServiceID.Hex(), err)
+            continue
+        }
+        target.PreviousStatus = service.Status
+        targets = append(targets, target)
+    }
+
+    // Services the incident no longer targets
+    for _, target := range applied {
+        restoreServiceStatus(hub, after, target, actor)
+    }
+
+    return targets
+}
+
+// restoreServiceStatus puts a service back to its status before the incident,
+// unless someone has changed it since
+func restoreServiceStatus(hub *websocket.Hub, incident models.Incident, target models.ServiceImpact, actor string) {
+    if target.PreviousStatus == "" || target.PreviousStatus == target.Status {
+        return
+    }
+
+    _, err := ReplaceServiceStatus(hub, incident.OrganizationID, target.ServiceID, target.Status, target.PreviousStatus, "Incident resolved: "+incident.Title, actor)
+    if err != nil && err != mongo.ErrNoDocuments {
+        log.Printf("Error restoring status of %s: %v", target.ServiceID.Hex(), err)
+    }
+}
+
+// storeServiceImpacts saves the targets returned by syncIncidentImpact
+func storeServiceImpacts(incidentID primitive.ObjectID, targets []models.ServiceImpact) {
+    _, err := database.GetCollection("incidents").UpdateOne(
+        context.TODO(),
+        bson.M{"_id": incidentID},
+        bson.M{"$set": bson.M{"service_statuses": targets}},
+    )
+    if err != nil {
+        log.Printf("Error storing service statuses for incident %s: %v", incidentID.Hex(), err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+    "context"
+    "errors"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+var errTemplateNotFound = errors.New("Incident template not found")
+
+// validateIncidentTemplate applies the same rules to a template's defaults as
+// to an incident's impact and affected services
+func validateIncidentTemplate(orgID primitive.ObjectID, template *models.IncidentTemplate) (int, error) {
+    if template.Name == "" {
+        return http.StatusBadRequest, errors.New("Name is required")
+    }
+    if template.Title == "" {
+        return http.StatusBadRequest, errors.New("Title is required")
+    }
+
+    defaults := models.Incident{
+        Impact:           template.Impact,
+        AffectedServices: template.AffectedServices,
+        ServiceStatuses:  template.ServiceStatuses,
+    }
+    if err := validateIncidentImpact(&defaults); err != nil {
+        return http.StatusBadRequest, err
+    }
+    ok, err := servicesBelongTo(orgID, defaults.AffectedServices)
+    if err != nil {
+        log.Printf("Error checking affected services: %v", err)
+        return http.StatusInternalServerError, errors.New("Database error")
+    }
+    if !ok {
+        return http.StatusBadRequest, errors.New("Affected service not found")
+    }
+
+    template.Impact = defaults.Impact
+    template.AffectedServices = defaults.AffectedServices
+    if template.AffectedServices == nil {
+        template.AffectedServices = make([]primitive.ObjectID, 0)
+    }
+    return http.StatusOK, nil
+}
+
+// applyIncidentTemplate fills in the fields the request left empty from the
+// template. Variables are substituted only into text taken from the template,
+// a title or description the caller wrote is kept as is.
+func applyIncidentTemplate(orgID primitive.ObjectID, templateID string, vars map[string]string, incident *models.Incident) error {
+    objID, err := primitive.ObjectIDFromHex(templateID)
+    if err != nil {
+        return errTemplateNotFound
+    }
+
+    var template models.IncidentTemplate
+    err = database.GetCollection("incident_templates").FindOne(context.TODO(), bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&template)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            return errTemplateNotFound
+        }
+        return err
+    }
+
+    if incident.Title == "" {
+        if incident.Title, err = models.RenderTemplate(template.Title, vars); err != nil {
+            return err
+        }
+    }
+    if incident.Description == "" {
+        if incident.Description, err = models.RenderTemplate(template.Description, vars); err != nil {
+            return err
+        }
+    }
+    if incident.Type == "" {
+        incident.Type = template.Type
+    }
+    if incident.Impact == "" {
+        incident.Impact = template.Impact
+    }
+    if incident.AffectedServices == nil {
+        incident.AffectedServices = template.AffectedServices
+    }
+    if incident.ServiceStatuses == nil {
+        incident.ServiceStatuses = template.ServiceStatuses
+    }
+    return nil
+}
+
+func GetIncidentTemplates(c *gin.Context) {
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    collection := database.GetCollection("incident_templates")
+    cursor, err := collection.Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        options.Find().SetSort(bson.D{{Key: "name", Value: 1}}),
+    )
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch incident templates"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    templates := make([]models.IncidentTemplate, 0)
+    if err := cursor.All(context.TODO(), &templates); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode incident templates"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func CreateIncidentTemplate(c *gin.Context) {
+    var template models.IncidentTemplate
+    if err := c.ShouldBindJSON(&template); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    if status, err := validateIncidentTemplate(orgID, &template); err != nil {
+        c.JSON(status, gin.H{"error": err.Error()})
+        return
+    }
+
+    template.ID = primitive.NilObjectID
+    template.OrganizationID = orgID
+    template.CreatedBy = c.GetString("user_id")
+    template.Deleted = false
+    template.CreatedAt = time.Now()
+    template.UpdatedAt = time.Now()
+
+    result, err := database.GetCollection("incident_templates").InsertOne(context.TODO(), template)
+    if err != nil {
+        log.Printf("Error creating incident template: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create incident template"})
+        return
+    }
+
+    template.ID = result.InsertedID.(primitive.ObjectID)
+
+    log.Printf("✅ Incident template created: %s", template.Name)
+    c.JSON(http.StatusCreated, gin.H{"template": template})
+}
+
+// UpdateIncidentTemplate changes only the fields present in the request
+func UpdateIncidentTemplate(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident template ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Name             *string                 `json:"name"`
+        Title            *string                 `json:"title"`
+        Description      *string                 `json:"description"`
+        Type             *string                 `json:"type"`
+        Impact           *models.IncidentImpact  `json:"impact"`
+        AffectedServices *[]primitive.ObjectID   `json:"affected_services"`
+        ServiceStatuses  *[]models.ServiceImpact `json:"service_statuses"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    collection := database.GetCollection("incident_templates")
+    filter := bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+
+    var template models.IncidentTemplate
+    if err := collection.FindOne(context.TODO(), filter).Decode(&template); err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Incident template not found"})
+        } else {
+            log.Printf("Error finding incident template: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    if update.Name != nil {
+        template.Name = *update.Name
+    }
+    if update.Title != nil {
+        template.Title = *update.Title
+    }
+    if update.Description != nil {
+        template.Description = *update.Description
+    }
+    if update.Type != nil {
+        template.Type = *update.Type
+    }
+    if update.Impact != nil {
+        template.Impact = *update.Impact
+    }
+    if update.AffectedServices != nil {
+        template.AffectedServices = *update.AffectedServices
+    }
+    if update.ServiceStatuses != nil {
+        template.ServiceStatuses = *update.ServiceStatuses
+    }
+
+    if status, err := validateIncidentTemplate(orgID, &template); err != nil {
+        c.JSON(status, gin.H{"error": err.Error()})
+        return
+    }
+
+    template.UpdatedAt = time.Now()
+    _, err = collection.UpdateOne(context.TODO(), filter, bson.M{
+        "$set": bson.M{
+            "name":              template.Name,
+            "title":             template.Title,
+            "description":       template.Description,
+            "type":              template.Type,
+            "impact":            template.Impact,
+            "affected_services": template.AffectedServices,
+            "service_statuses":  template.ServiceStatuses,
+            "updated_at":        template.UpdatedAt,
+        },
+    })
+    if err != nil {
+        log.Printf("Error updating incident template: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident template"})
+        return
+    }
+
+    log.Printf("✅ Incident template updated: %s", template.Name)
+    c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+func DeleteIncidentTemplate(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident template ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    // Soft delete: set deleted = true
+    result, err := database.GetCollection("incident_templates").UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    )
+    if err != nil {
+        log.Printf("Error deleting incident template: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete incident template"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Incident template not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"message": "Incident template deleted successfully"})
+}
\ No newline at end of file
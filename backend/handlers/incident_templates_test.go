@@ -0,0 +1,91 @@
+package handlers
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestCreateIncidentFromTemplateRendersOnlyTemplateText(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    template := models.IncidentTemplate{
+        ID:               primitive.NewObjectID(),
+        OrganizationID:   orgID,
+        Name:             "Outage",
+        Title:            "{{service}} is down",
+        Description:      "We are investigating errors on {{service}} in {{region}}",
+        Type:             "incident",
+        Impact:           models.ImpactMajor,
+        AffectedServices: make([]primitive.ObjectID, 0),
+        CreatedAt:        time.Now(),
+        UpdatedAt:        time.Now(),
+    }
+    if _, err := database.GetCollection("incident_templates").InsertOne(context.TODO(), template); err != nil {
+        t.Fatal(err)
+    }
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/incidents", CreateIncident)
+    create := func(body map[string]interface{}) (int, models.Incident, string) {
+        body["template_id"] = template.ID.Hex()
+        w := doJSON(r, http.MethodPost, "/incidents", body)
+        var response struct {
+            Incident models.Incident `json:"incident"`
+        }
+        json.Unmarshal(w.Body.Bytes(), &response)
+        return w.Code, response.Incident, w.Body.String()
+    }
+
+    code, incident, body := create(map[string]interface{}{
+        "variables": map[string]string{"service": "API", "region": "eu-west-1"},
+    })
+    if code != http.StatusCreated {
+        t.Fatalf("from template = %d: %s", code, body)
+    }
+    if incident.Title != "API is down" || incident.Description != "We are investigating errors on API in eu-west-1" {
+        t.Errorf("rendered %q / %q", incident.Title, incident.Description)
+    }
+    if incident.Impact != models.ImpactMajor {
+        t.Errorf("impact = %s, want the template's", incident.Impact)
+    }
+
+    // Text the caller wrote is kept verbatim, even if it looks like a placeholder
+    code, incident, body = create(map[string]interface{}{
+        "title":     "Errors on {{service}} dashboard",
+        "variables": map[string]string{"service": "API", "region": "eu-west-1"},
+    })
+    if code != http.StatusCreated {
+        t.Fatalf("caller title = %d: %s", code, body)
+    }
+    if incident.Title != "Errors on {{service}} dashboard" {
+        t.Errorf("caller title rendered to %q", incident.Title)
+    }
+
+    code, _, body = create(map[string]interface{}{
+        "variables": map[string]string{"service": "API"},
+    })
+    if code != http.StatusBadRequest || !strings.Contains(body, "region") {
+        t.Errorf("missing variable = %d %s, want 400 naming region", code, body)
+    }
+
+    // The description needing region comes from the caller, so nothing is missing
+    code, incident, body = create(map[string]interface{}{
+        "description": "Looking into it",
+        "variables":   map[string]string{"service": "API"},
+    })
+    if code != http.StatusCreated {
+        t.Fatalf("caller description = %d: %s", code, body)
+    }
+    if incident.Title != "API is down" || incident.Description != "Looking into it" {
+        t.Errorf("got %q / %q", incident.Title, incident.Description)
+    }
+}
\ No newline at end of file
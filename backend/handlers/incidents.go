@@ -10,6 +10,7 @@ import (
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/bson/primitive"
     "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
 
     "status-page-backend/database"
     "status-page-backend/models"
@@ -42,55 +43,104 @@ func GetIncidents(c *gin.Context) {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode incidents"})
         return
     }
+    withTimelines(incidents)
 
     c.JSON(http.StatusOK, gin.H{"incidents": incidents})
 }
 
+// createIncidentRequest is the body of POST /incidents. The ID, status,
+// timeline and postmortem are set by the server, never by the caller.
+type createIncidentRequest struct {
+    Title            string                 `json:"title"`
+    Description      string                 `json:"description"`
+    Type             string                 `json:"type"`
+    Impact           models.IncidentImpact  `json:"impact"`
+    AffectedServices []primitive.ObjectID   `json:"affected_services"`
+    ServiceStatuses  []models.ServiceImpact `json:"service_statuses"`
+    TemplateID       string                 `json:"template_id"`
+    Variables        map[string]string      `json:"variables"`
+}
+
+func (r createIncidentRequest) incident() models.Incident {
+    return models.Incident{
+        Title:            r.Title,
+        Description:      r.Description,
+        Type:             r.Type,
+        Impact:           r.Impact,
+        AffectedServices: r.AffectedServices,
+        ServiceStatuses:  r.ServiceStatuses,
+    }
+}
+
+// CreateIncident opens an incident. With a template_id, fields left out of
+// the request come from the template and its {{variables}} are filled in.
 func CreateIncident(c *gin.Context) {
-    var incident models.Incident
-    if err := c.ShouldBindJSON(&incident); err != nil {
+    var request createIncidentRequest
+    if err := c.ShouldBindJSON(&request); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
+    incident := request.incident()
 
     orgID := c.GetString("organization_id")
     incident.OrganizationID, _ = primitive.ObjectIDFromHex(orgID)
+
+    if request.TemplateID != "" {
+        if err := applyIncidentTemplate(incident.OrganizationID, request.TemplateID, request.Variables, &incident); err != nil {
+            if err == errTemplateNotFound {
+                c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+            } else if _, missing := err.(models.MissingVariablesError); missing {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            } else {
+                log.Printf("Error loading incident template: %v", err)
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            }
+            return
+        }
+    }
     incident.Status = models.IncidentStatusInvestigating
     incident.CreatedAt = time.Now()
     incident.UpdatedAt = time.Now()
     incident.CreatedBy = c.GetString("user_id")
+    incident.ResolvedAt = nil
 
-    collection := database.GetCollection("incidents")
-    result, err := collection.InsertOne(context.TODO(), incident)
+    if err := validateIncidentImpact(&incident); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    ok, err := servicesBelongTo(incident.OrganizationID, incident.AffectedServices)
     if err != nil {
-        log.Printf("Error creating incident: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create incident"})
+        log.Printf("Error checking affected services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
         return
     }
 
-    incident.ID = result.InsertedID.(primitive.ObjectID)
+    // The description opens the timeline
+    incident.Updates = []models.IncidentUpdate{{
+        ID:        primitive.NewObjectID(),
+        Status:    incident.Status,
+        Message:   incident.Description,
+        CreatedBy: incident.CreatedBy,
+        CreatedAt: incident.CreatedAt,
+    }}
 
-    // Broadcast incident creation via WebSocket
-    websocketMessage := websocket.Message{
-        Type: "incident_created",
-        Data: map[string]interface{}{
-            "incident_id":       incident.ID.Hex(),
-            "incident_title":    incident.Title,
-            "incident_desc":     incident.Description,
-            "incident_status":   string(incident.Status),
-            "incident_type":     incident.Type,
-            "organization_id":   incident.OrganizationID.Hex(),
-            "affected_services": incident.AffectedServices,
-            "action":            "incident_created",
-            "timestamp":         time.Now().Unix(),
-        },
+    incident, err = InsertIncident(websocketHub(c), incident)
+    if err != nil {
+        log.Printf("Error creating incident: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create incident"})
+        return
     }
-    BroadcastWebSocket(c, websocketMessage)
 
     log.Printf("✅ Incident created: %s", incident.Title)
     c.JSON(http.StatusCreated, gin.H{"incident": incident})
 }
 
+// UpdateIncident changes only the fields present in the request, so
+// {"status": "resolved"} leaves the title and services alone
 func UpdateIncident(c *gin.Context) {
     incidentID := c.Param("id")
     objID, err := primitive.ObjectIDFromHex(incidentID)
@@ -99,12 +149,20 @@ func UpdateIncident(c *gin.Context) {
         return
     }
 
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
     var update struct {
-        Title            string                   `json:"title"`
-        Description      string                   `json:"description"`
-        Status           models.IncidentStatus    `json:"status"`
-        Type             string                   `json:"type"`
-        AffectedServices []primitive.ObjectID     `json:"affected_services"`
+        Title            *string                  `json:"title"`
+        Description      *string                  `json:"description"`
+        Status           *models.IncidentStatus   `json:"status"`
+        Type             *string                  `json:"type"`
+        Impact           *models.IncidentImpact   `json:"impact"`
+        AffectedServices *[]primitive.ObjectID    `json:"affected_services"`
+        ServiceStatuses  *[]models.ServiceImpact  `json:"service_statuses"`
     }
 
     if err := c.ShouldBindJSON(&update); err != nil {
@@ -112,12 +170,12 @@ func UpdateIncident(c *gin.Context) {
         return
     }
 
-    // Get existing incident for broadcasting
     collection := database.GetCollection("incidents")
     var existingIncident models.Incident
     err = collection.FindOne(context.TODO(), bson.M{
-        "_id":     objID,
-        "deleted": bson.M{"$ne": true},
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
     }).Decode(&existingIncident)
     
     if err != nil {
@@ -130,27 +188,107 @@ func UpdateIncident(c *gin.Context) {
         return
     }
 
-    updateDoc := bson.M{
-        "$set": bson.M{
-            "title":             update.Title,
-            "description":       update.Description,
-            "status":            update.Status,
-            "type":              update.Type,
-            "affected_services": update.AffectedServices,
-            "updated_at":        time.Now(),
-        },
+    // Merge the request into a copy of the stored incident
+    merged := existingIncident
+    merged.AffectedServices = append([]primitive.ObjectID(nil), existingIncident.AffectedServices...)
+    merged.ServiceStatuses = append([]models.ServiceImpact(nil), existingIncident.ServiceStatuses...)
+    set := bson.M{}
+
+    if update.Title != nil {
+        if *update.Title == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+            return
+        }
+        merged.Title = *update.Title
+        set["title"] = merged.Title
+    }
+    if update.Description != nil {
+        merged.Description = *update.Description
+        set["description"] = merged.Description
+    }
+    if update.Type != nil {
+        merged.Type = *update.Type
+        set["type"] = merged.Type
+    }
+    if update.Status != nil {
+        if !update.Status.Valid() {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident status"})
+            return
+        }
+        if !existingIncident.Status.CanMoveTo(*update.Status) {
+            c.JSON(http.StatusConflict, gin.H{"error": "Cannot move a " + string(existingIncident.Status) + " incident to " + string(*update.Status)})
+            return
+        }
+        merged.Status = *update.Status
+        set["status"] = merged.Status
+    }
+    if update.Impact != nil {
+        merged.Impact = *update.Impact
+    }
+    if update.AffectedServices != nil {
+        merged.AffectedServices = *update.AffectedServices
+    }
+    if update.ServiceStatuses != nil {
+        merged.ServiceStatuses = *update.ServiceStatuses
+    }
+    if update.Impact != nil || update.AffectedServices != nil || update.ServiceStatuses != nil {
+        if err := validateIncidentImpact(&merged); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        ok, err := servicesBelongTo(orgID, merged.AffectedServices)
+        if err != nil {
+            log.Printf("Error checking affected services: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if !ok {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
+            return
+        }
+        set["impact"] = merged.Impact
+        set["affected_services"] = merged.AffectedServices
     }
 
-    // If resolving, set resolved_at
-    if update.Status == models.IncidentStatusResolved {
-        updateDoc["$set"].(bson.M)["resolved_at"] = time.Now()
+    if len(set) == 0 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+        return
     }
 
-    _, err = collection.UpdateOne(
+    now := time.Now()
+    merged.UpdatedAt = now
+    set["updated_at"] = now
+    updateDoc := bson.M{"$set": set}
+
+    // A status change goes on the timeline so the narrative isn't lost
+    if merged.Status != existingIncident.Status {
+        entry := models.IncidentUpdate{
+            ID:        primitive.NewObjectID(),
+            Status:    merged.Status,
+            Message:   "Status changed to " + string(merged.Status),
+            CreatedBy: c.GetString("user_id"),
+            CreatedAt: now,
+        }
+        merged.Updates = append(merged.Updates, entry)
+        updateDoc["$push"] = bson.M{"updates": entry}
+
+        if merged.Status == models.IncidentStatusResolved {
+            merged.ResolvedAt = &now
+            set["resolved_at"] = now
+        } else {
+            merged.ResolvedAt = nil
+            updateDoc["$unset"] = bson.M{"resolved_at": ""}
+        }
+    }
+
+    // Only apply the change if nobody moved the incident since it was read
+    result, err := collection.UpdateOne(
         context.TODO(), 
         bson.M{
-            "_id":     objID,
-            "deleted": bson.M{"$ne": true},
+            "_id":             objID,
+            "organization_id": orgID,
+            "status":          existingIncident.Status,
+            "deleted":         bson.M{"$ne": true},
         }, 
         updateDoc,
     )
@@ -159,25 +297,226 @@ func UpdateIncident(c *gin.Context) {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident"})
         return
     }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusConflict, gin.H{"error": "Incident changed status, try again"})
+        return
+    }
+
+    // Move the affected services once the incident is stored, then record
+    // what they were moved from
+    if update.ServiceStatuses != nil || merged.Status != existingIncident.Status {
+        merged.ServiceStatuses = syncIncidentImpact(websocketHub(c), existingIncident, merged, c.GetString("user_id"))
+        storeServiceImpacts(merged.ID, merged.ServiceStatuses)
+    }
+
+    broadcastIncidentUpdate(websocketHub(c), merged, existingIncident.Status, "")
+
+    log.Printf("✅ Incident updated: %s (%s -> %s)", merged.Title, existingIncident.Status, merged.Status)
+    c.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully", "incident": merged})
+}
+
+// CreateIncidentUpdate posts a new entry to an incident's timeline. The
+// incident takes the status of its latest update.
+func CreateIncidentUpdate(c *gin.Context) {
+    incidentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var request struct {
+        Status  models.IncidentStatus `json:"status" binding:"required"`
+        Message string                `json:"message" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&request); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    if !request.Status.Valid() {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident status"})
+        return
+    }
+
+    var existingIncident models.Incident
+    err = database.GetCollection("incidents").FindOne(context.TODO(), bson.M{
+        "_id":             incidentID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&existingIncident)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+        } else {
+            log.Printf("Error finding incident: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+    if !existingIncident.Status.CanMoveTo(request.Status) {
+        c.JSON(http.StatusConflict, gin.H{"error": "Cannot move a " + string(existingIncident.Status) + " incident to " + string(request.Status)})
+        return
+    }
+
+    // Only move on from the status the transition was checked against
+    incident, err := AddIncidentUpdate(websocketHub(c), orgID, incidentID, existingIncident.Status, request.Status, request.Message, c.GetString("user_id"))
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusConflict, gin.H{"error": "Incident changed status, try again"})
+        } else {
+            log.Printf("Error adding incident update: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add incident update"})
+        }
+        return
+    }
 
-    // Broadcast incident update via WebSocket
+    c.JSON(http.StatusCreated, gin.H{"incident": incident})
+}
+
+// withTimelines fills in the timeline of incidents stored without one
+func withTimelines(incidents []models.Incident) {
+    for i := range incidents {
+        incidents[i].Updates = incidents[i].Timeline()
+    }
+}
+
+// InsertIncident stores a new incident, moves its services to their target
+// statuses and broadcasts it. It is shared by CreateIncident and incidents
+// opened automatically.
+func InsertIncident(hub *websocket.Hub, incident models.Incident) (models.Incident, error) {
+    if incident.Impact == "" {
+        incident.Impact = models.ImpactNone
+    }
+
+    collection := database.GetCollection("incidents")
+    result, err := collection.InsertOne(context.TODO(), incident)
+    if err != nil {
+        return incident, err
+    }
+
+    incident.ID = result.InsertedID.(primitive.ObjectID)
+
+    if len(incident.ServiceStatuses) > 0 {
+        incident.ServiceStatuses = syncIncidentImpact(hub, models.Incident{}, incident, incident.CreatedBy)
+        storeServiceImpacts(incident.ID, incident.ServiceStatuses)
+    }
+
+    // Broadcast incident creation via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "incident_created",
+        Data: map[string]interface{}{
+            "incident_id":       incident.ID.Hex(),
+            "incident_title":    incident.Title,
+            "incident_desc":     incident.Description,
+            "incident_status":   string(incident.Status),
+            "incident_type":     incident.Type,
+            "incident_impact":   string(incident.Impact),
+            "organization_id":   incident.OrganizationID.Hex(),
+            "affected_services": incident.AffectedServices,
+            "service_statuses":  incident.ServiceStatuses,
+            "action":            "incident_created",
+            "timestamp":         time.Now().Unix(),
+        },
+    }
+    Broadcast(hub, websocketMessage)
+
+    return incident, nil
+}
+
+// AddIncidentUpdate appends an entry to the incident's timeline, moves the
+// incident to the entry's status and broadcasts the change. Resolving the
+// incident hands its services back their previous statuses. The update only
+// applies while the incident is still in status from, an empty from matches
+// any status. It returns mongo.ErrNoDocuments if the incident has moved on.
+func AddIncidentUpdate(hub *websocket.Hub, orgID, incidentID primitive.ObjectID, from, status models.IncidentStatus, message, author string) (models.Incident, error) {
+    now := time.Now()
+    update := models.IncidentUpdate{
+        ID:        primitive.NewObjectID(),
+        Status:    status,
+        Message:   message,
+        CreatedBy: author,
+        CreatedAt: now,
+    }
+
+    updateDoc := bson.M{
+        "$push": bson.M{"updates": update},
+        "$set": bson.M{
+            "status":     status,
+            "updated_at": now,
+        },
+    }
+    if status == models.IncidentStatusResolved {
+        updateDoc["$set"].(bson.M)["resolved_at"] = now
+    } else {
+        updateDoc["$unset"] = bson.M{"resolved_at": ""}
+    }
+
+    filter := bson.M{
+        "_id":             incidentID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+    if from != "" {
+        filter["status"] = from
+    }
+
+    collection := database.GetCollection("incidents")
+    var existingIncident models.Incident
+    err := collection.FindOneAndUpdate(
+        context.TODO(),
+        filter,
+        updateDoc,
+        options.FindOneAndUpdate().SetReturnDocument(options.Before),
+    ).Decode(&existingIncident)
+    if err != nil {
+        return existingIncident, err
+    }
+
+    incident := existingIncident
+    incident.Status = status
+    incident.UpdatedAt = now
+    incident.Updates = append(incident.Updates, update)
+    if status == models.IncidentStatusResolved {
+        incident.ResolvedAt = &now
+    } else {
+        incident.ResolvedAt = nil
+    }
+
+    if status != existingIncident.Status && len(incident.ServiceStatuses) > 0 {
+        incident.ServiceStatuses = syncIncidentImpact(hub, existingIncident, incident, author)
+        storeServiceImpacts(incident.ID, incident.ServiceStatuses)
+    }
+
+    broadcastIncidentUpdate(hub, incident, existingIncident.Status, message)
+
+    log.Printf("✅ Incident updated: %s (%s -> %s)", incident.Title, existingIncident.Status, status)
+    return incident, nil
+}
+
+// broadcastIncidentUpdate sends the incident as stored after a change
+func broadcastIncidentUpdate(hub *websocket.Hub, incident models.Incident, oldStatus models.IncidentStatus, message string) {
     websocketMessage := websocket.Message{
         Type: "incident_updated",
         Data: map[string]interface{}{
-            "incident_id":       incidentID,
-            "incident_title":    update.Title,
-            "incident_desc":     update.Description,
-            "old_status":        string(existingIncident.Status),
-            "new_status":        string(update.Status),
-            "incident_type":     update.Type,
-            "organization_id":   existingIncident.OrganizationID.Hex(),
-            "affected_services": update.AffectedServices,
+            "incident_id":       incident.ID.Hex(),
+            "incident_title":    incident.Title,
+            "incident_desc":     incident.Description,
+            "old_status":        string(oldStatus),
+            "new_status":        string(incident.Status),
+            "incident_type":     incident.Type,
+            "incident_impact":   string(incident.Impact),
+            "organization_id":   incident.OrganizationID.Hex(),
+            "affected_services": incident.AffectedServices,
+            "service_statuses":  incident.ServiceStatuses,
+            "update_message":    message,
             "action":            "incident_updated",
             "timestamp":         time.Now().Unix(),
         },
     }
-    BroadcastWebSocket(c, websocketMessage)
-
-    log.Printf("✅ Incident updated: %s (%s -> %s)", update.Title, existingIncident.Status, update.Status)
-    c.JSON(http.StatusOK, gin.H{"message": "Incident updated successfully"})
+    Broadcast(hub, websocketMessage)
 }
\ No newline at end of file
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "reflect"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/middleware"
+    "status-page-backend/models"
+)
+
+func TestCreateIncidentRequestDropsServerFields(t *testing.T) {
+    body := `{
+        "id": "64b7f0c2a1b2c3d4e5f60718",
+        "title": "API errors",
+        "status": "resolved",
+        "created_by": "someone-else",
+        "updates": [{"message": "forged"}],
+        "postmortem": {"body": "forged", "status": "published"}
+    }`
+    var request createIncidentRequest
+    if err := json.Unmarshal([]byte(body), &request); err != nil {
+        t.Fatal(err)
+    }
+    incident := request.incident()
+
+    if incident.Title != "API errors" {
+        t.Errorf("title = %q", incident.Title)
+    }
+    if !incident.ID.IsZero() || incident.Status != "" || incident.CreatedBy != "" {
+        t.Errorf("server fields taken from the body: %+v", incident)
+    }
+    if incident.Updates != nil || incident.Postmortem != nil {
+        t.Errorf("timeline or postmortem taken from the body: %+v", incident)
+    }
+}
+
+func TestCreateIncidentEditorCannotPublishPostmortem(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/incidents", middleware.RequirePermission(models.PermIncidentsWrite), CreateIncident)
+
+    forgedID := primitive.NewObjectID()
+    w := doJSON(r, http.MethodPost, "/incidents", map[string]interface{}{
+        "id":    forgedID.Hex(),
+        "title": "API errors",
+        "type":  "incident",
+        "postmortem": map[string]interface{}{
+            "body":   "Nothing to see here",
+            "status": "published",
+        },
+    })
+    if w.Code != http.StatusCreated {
+        t.Fatalf("status = %d: %s", w.Code, w.Body)
+    }
+
+    var response struct {
+        Incident models.Incident `json:"incident"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &response)
+    if response.Incident.ID == forgedID {
+        t.Errorf("incident stored under the client-supplied ID")
+    }
+    stored := findIncident(t, response.Incident.ID)
+    if stored.Postmortem != nil {
+        t.Errorf("postmortem stored from the create request: %+v", stored.Postmortem)
+    }
+    if stored.Public().Postmortem != nil {
+        t.Errorf("postmortem published on the status page")
+    }
+}
+
+func TestUpdateIncidentPatchKeepsOtherFields(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+    incident := insertIncident(t, orgID, "API errors", service.ID)
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.PATCH("/incidents/:id", UpdateIncident)
+
+    w := doJSON(r, http.MethodPatch, "/incidents/"+incident.ID.Hex(), map[string]interface{}{"status": "identified"})
+    if w.Code != http.StatusOK {
+        t.Fatalf("status = %d: %s", w.Code, w.Body)
+    }
+
+    stored := findIncident(t, incident.ID)
+    if stored.Status != models.IncidentStatusIdentified {
+        t.Errorf("status = %s, want identified", stored.Status)
+    }
+    if stored.Title != incident.Title || stored.Type != incident.Type || stored.Impact != incident.Impact {
+        t.Errorf("fields left out of the patch changed: %+v", stored)
+    }
+    if len(stored.AffectedServices) != 1 || stored.AffectedServices[0] != service.ID {
+        t.Errorf("affected services = %v", stored.AffectedServices)
+    }
+    if len(stored.Updates) != 1 || stored.Updates[0].Status != models.IncidentStatusIdentified {
+        t.Errorf("timeline = %+v, want one identified entry", stored.Updates)
+    }
+}
+
+func TestUpdateIncidentRejectsBadStatus(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    open := insertIncident(t, orgID, "API errors")
+    resolved := insertIncident(t, orgID, "Old errors")
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.PATCH("/incidents/:id", UpdateIncident)
+    if w := doJSON(r, http.MethodPatch, "/incidents/"+resolved.ID.Hex(), map[string]interface{}{"status": "resolved"}); w.Code != http.StatusOK {
+        t.Fatalf("resolve = %d: %s", w.Code, w.Body)
+    }
+    openBefore := findIncident(t, open.ID)
+    resolvedBefore := findIncident(t, resolved.ID)
+
+    tests := []struct {
+        id     primitive.ObjectID
+        status string
+        want   int
+    }{
+        {open.ID, "fixed", http.StatusBadRequest},
+        {open.ID, "", http.StatusBadRequest},
+        {resolved.ID, "monitoring", http.StatusConflict},
+        {resolved.ID, "identified", http.StatusConflict},
+    }
+    for _, tt := range tests {
+        w := doJSON(r, http.MethodPatch, "/incidents/"+tt.id.Hex(), map[string]interface{}{"status": tt.status})
+        if w.Code != tt.want {
+            t.Errorf("status %q = %d, want %d: %s", tt.status, w.Code, tt.want, w.Body)
+        }
+    }
+
+    if after := findIncident(t, open.ID); !reflect.DeepEqual(after, openBefore) {
+        t.Errorf("open incident changed: %+v", after)
+    }
+    if after := findIncident(t, resolved.ID); !reflect.DeepEqual(after, resolvedBefore) {
+        t.Errorf("resolved incident changed: %+v", after)
+    }
+}
+
+func TestUpdateIncidentBroadcastsMergedIncident(t *testing.T) {
+    setupTestDB(t)
+    hub, next := listenBroadcasts(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+    incident := insertIncident(t, orgID, "API errors", service.ID)
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.Use(func(c *gin.Context) { c.Set("websocket_hub", hub) })
+    r.PATCH("/incidents/:id", UpdateIncident)
+
+    w := doJSON(r, http.MethodPatch, "/incidents/"+incident.ID.Hex(), map[string]interface{}{"status": "monitoring"})
+    if w.Code != http.StatusOK {
+        t.Fatalf("status = %d: %s", w.Code, w.Body)
+    }
+
+    data := next("incident_updated")
+    want := map[string]interface{}{
+        "incident_id":     incident.ID.Hex(),
+        "incident_title":  "API errors",
+        "incident_type":   "incident",
+        "incident_impact": "minor",
+        "old_status":      "investigating",
+        "new_status":      "monitoring",
+    }
+    for key, value := range want {
+        if data[key] != value {
+            t.Errorf("%s = %v, want %v", key, data[key], value)
+        }
+    }
+    if services, _ := data["affected_services"].([]interface{}); len(services) != 1 || services[0] != service.ID.Hex() {
+        t.Errorf("affected_services = %v", data["affected_services"])
+    }
+}
+
+func TestUpdateIncidentRestoresServicesOnResolve(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    api := insertService(t, orgID, "API")
+    web := insertService(t, orgID, "Web")
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/incidents", CreateIncident)
+    r.PATCH("/incidents/:id", UpdateIncident)
+
+    w := doJSON(r, http.MethodPost, "/incidents", map[string]interface{}{
+        "title":  "Outage",
+        "impact": "major",
+        "service_statuses": []map[string]interface{}{
+            {"service_id": api.ID.Hex(), "status": "major_outage"},
+            {"service_id": web.ID.Hex(), "status": "degraded_performance"},
+        },
+    })
+    if w.Code != http.StatusCreated {
+        t.Fatalf("create = %d: %s", w.Code, w.Body)
+    }
+    var response struct {
+        Incident models.Incident `json:"incident"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &response)
+    if got := findService(t, api.ID).Status; got != models.StatusMajorOutage {
+        t.Fatalf("api status = %s, want major_outage", got)
+    }
+
+    w = doJSON(r, http.MethodPatch, "/incidents/"+response.Incident.ID.Hex(), map[string]interface{}{"status": "resolved"})
+    if w.Code != http.StatusOK {
+        t.Fatalf("resolve = %d: %s", w.Code, w.Body)
+    }
+
+    for _, service := range []models.Service{api, web} {
+        if got := findService(t, service.ID).Status; got != models.StatusOperational {
+            t.Errorf("%s status = %s, want operational", service.Name, got)
+        }
+    }
+    stored := findIncident(t, response.Incident.ID)
+    if stored.ResolvedAt == nil {
+        t.Errorf("resolved_at not set")
+    }
+    for _, target := range stored.ServiceStatuses {
+        if target.PreviousStatus != models.StatusOperational {
+            t.Errorf("previous status of %s = %q, want operational", target.ServiceID.Hex(), target.PreviousStatus)
+        }
+    }
+}
+
+// Two updates checked against the same status can't both apply, so a
+// resolved incident can't be moved on to a status the matrix forbids
+func TestAddIncidentUpdateGuardsOnStatus(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    incident := insertIncident(t, orgID, "API errors")
+
+    _, err := AddIncidentUpdate(nil, orgID, incident.ID, models.IncidentStatusInvestigating, models.IncidentStatusResolved, "Fixed", "test-user")
+    if err != nil {
+        t.Fatal(err)
+    }
+    _, err = AddIncidentUpdate(nil, orgID, incident.ID, models.IncidentStatusInvestigating, models.IncidentStatusMonitoring, "Watching", "test-user")
+    if err != mongo.ErrNoDocuments {
+        t.Fatalf("stale update err = %v, want mongo.ErrNoDocuments", err)
+    }
+    if got := findIncident(t, incident.ID); got.Status != models.IncidentStatusResolved || len(got.Updates) != 1 {
+        t.Errorf("incident = %s with %d updates, want resolved with 1", got.Status, len(got.Updates))
+    }
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/incidents/:id/updates", CreateIncidentUpdate)
+    w := doJSON(r, http.MethodPost, "/incidents/"+incident.ID.Hex()+"/updates", map[string]interface{}{"status": "monitoring", "message": "Watching"})
+    if w.Code != http.StatusConflict {
+        t.Errorf("resolved to monitoring = %d, want 409: %s", w.Code, w.Body)
+    }
+}
\ No newline at end of file
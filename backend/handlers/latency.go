@@ -0,0 +1,231 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "math"
+    "net/http"
+    "sort"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/uptime"
+)
+
+// LatencyRetention is how long latency samples are kept
+const LatencyRetention = 30 * 24 * time.Hour
+
+type latencyStats struct {
+    From  time.Time `json:"from"`
+    To    time.Time `json:"to"`
+    Count int       `json:"count"`
+    Min   float64   `json:"min_ms"`
+    Max   float64   `json:"max_ms"`
+    Avg   float64   `json:"avg_ms"`
+    P50   float64   `json:"p50_ms"`
+    P95   float64   `json:"p95_ms"`
+    P99   float64   `json:"p99_ms"`
+}
+
+// latencyBinGrowth is the width of the histogram bins latency is aggregated
+// into, each bin's upper edge is 5% above its lower edge. Percentiles are
+// read from the bins, so they are within 5% of the exact value.
+const latencyBinGrowth = 1.05
+
+// latencyBin is one histogram bin of one time bucket, as aggregated by MongoDB
+type latencyBin struct {
+    Bucket float64 `bson:"bucket"`
+    Bin    float64 `bson:"bin"`
+    Count  int     `bson:"count"`
+    Min    float64 `bson:"min"`
+    Max    float64 `bson:"max"`
+    Sum    float64 `bson:"sum"`
+}
+
+// summarizeLatency computes aggregates over histogram bins. Count, min, max
+// and avg are exact, percentiles are the largest latency in the bin holding
+// that rank. The slice is sorted in place.
+func summarizeLatency(from, to time.Time, bins []latencyBin) latencyStats {
+    stats := latencyStats{From: from, To: to}
+    if len(bins) == 0 {
+        return stats
+    }
+
+    sort.Slice(bins, func(i, j int) bool { return bins[i].Bin < bins[j].Bin })
+    var sum float64
+    stats.Min = bins[0].Min
+    for _, bin := range bins {
+        stats.Count += bin.Count
+        sum += bin.Sum
+        stats.Min = math.Min(stats.Min, bin.Min)
+        stats.Max = math.Max(stats.Max, bin.Max)
+    }
+
+    stats.Avg = sum / float64(stats.Count)
+    stats.P50 = percentile(bins, stats.Count, 50)
+    stats.P95 = percentile(bins, stats.Count, 95)
+    stats.P99 = percentile(bins, stats.Count, 99)
+    return stats
+}
+
+// percentile uses the nearest-rank method on bins sorted by latency
+func percentile(bins []latencyBin, count int, p float64) float64 {
+    rank := int(math.Ceil(p / 100 * float64(count)))
+    seen := 0
+    for _, bin := range bins {
+        seen += bin.Count
+        if seen >= rank {
+            return bin.Max
+        }
+    }
+    return bins[len(bins)-1].Max
+}
+
+func GetServiceLatency(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    to := time.Now()
+    from := to.Add(-uptime.Windows["day"])
+
+    if name := c.Query("window"); name != "" {
+        d, ok := uptime.Windows[name]
+        if !ok || d > LatencyRetention {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Window must be one of day, week or month"})
+            return
+        }
+        from = to.Add(-d)
+    }
+    if fromParam := c.Query("from"); fromParam != "" {
+        if from, err = time.Parse(time.RFC3339, fromParam); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 time"})
+            return
+        }
+    }
+    if toParam := c.Query("to"); toParam != "" {
+        if to, err = time.Parse(time.RFC3339, toParam); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 time"})
+            return
+        }
+    }
+    if !from.Before(to) || to.Sub(from) > LatencyRetention {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Range must be positive and at most 30 days"})
+        return
+    }
+
+    var bucket time.Duration
+    if bucketParam := c.Query("bucket_minutes"); bucketParam != "" {
+        minutes, err := strconv.Atoi(bucketParam)
+        if err != nil || minutes < 1 {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "bucket_minutes must be a positive integer"})
+            return
+        }
+        bucket = time.Duration(minutes) * time.Minute
+        if to.Sub(from)/bucket > 1000 {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Too many buckets, use a larger bucket_minutes"})
+            return
+        }
+    }
+
+    count, err := database.GetCollection("services").CountDocuments(context.TODO(), bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    })
+    if err != nil {
+        log.Printf("Error finding service: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if count == 0 {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        return
+    }
+
+    // Samples are aggregated into a latency histogram per time bucket, so the
+    // response stays small however many checks ran in the range
+    bucketIndex := interface{}(bson.M{"$literal": 0})
+    if bucket > 0 {
+        bucketIndex = bson.M{"$floor": bson.M{"$divide": bson.A{
+            bson.M{"$subtract": bson.A{"$created_at", from}},
+            bucket.Milliseconds(),
+        }}}
+    }
+    pipeline := mongo.Pipeline{
+        // Failed checks are left out, timeouts would skew the percentiles
+        {{Key: "$match", Value: bson.M{
+            "service_id": objID,
+            "healthy":    true,
+            "created_at": bson.M{"$gte": from, "$lt": to},
+        }}},
+        {{Key: "$group", Value: bson.M{
+            "_id": bson.M{
+                "bucket": bucketIndex,
+                "bin": bson.M{"$floor": bson.M{"$divide": bson.A{
+                    bson.M{"$ln": bson.M{"$max": bson.A{"$latency_ms", 0.001}}},
+                    math.Log(latencyBinGrowth),
+                }}},
+            },
+            "count": bson.M{"$sum": 1},
+            "min":   bson.M{"$min": "$latency_ms"},
+            "max":   bson.M{"$max": "$latency_ms"},
+            "sum":   bson.M{"$sum": "$latency_ms"},
+        }}},
+        {{Key: "$project", Value: bson.M{
+            "bucket": "$_id.bucket",
+            "bin":    "$_id.bin",
+            "count":  1,
+            "min":    1,
+            "max":    1,
+            "sum":    1,
+        }}},
+    }
+    cursor, err := database.GetCollection("latency_samples").Aggregate(context.TODO(), pipeline)
+    if err != nil {
+        log.Printf("Error aggregating latency samples: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch latency"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var bins []latencyBin
+    if err := cursor.All(context.TODO(), &bins); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode latency"})
+        return
+    }
+
+    byBucket := make(map[int][]latencyBin)
+    for _, bin := range bins {
+        byBucket[int(bin.Bucket)] = append(byBucket[int(bin.Bucket)], bin)
+    }
+    response := gin.H{"service_id": objID, "latency": summarizeLatency(from, to, bins)}
+
+    if bucket > 0 {
+        series := make([]latencyStats, 0)
+        for i, start := 0, from; start.Before(to); i, start = i+1, start.Add(bucket) {
+            end := start.Add(bucket)
+            if end.After(to) {
+                end = to
+            }
+            series = append(series, summarizeLatency(start, end, byBucket[i]))
+        }
+        response["series"] = series
+    }
+
+    c.JSON(http.StatusOK, response)
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+    "context"
+    "encoding/json"
+    "math"
+    "net/http"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestSummarizeLatency(t *testing.T) {
+    from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+    to := from.Add(time.Hour)
+
+    if got := summarizeLatency(from, to, nil); got.Count != 0 || got.P99 != 0 {
+        t.Errorf("empty summary = %+v", got)
+    }
+
+    // 90 fast samples, 9 slower ones and a single outlier
+    bins := []latencyBin{
+        {Bin: 95, Count: 9, Min: 100, Max: 104, Sum: 918},
+        {Bin: 141, Count: 1, Min: 990, Max: 990, Sum: 990},
+        {Bin: 47, Count: 90, Min: 10, Max: 10.4, Sum: 918},
+    }
+    got := summarizeLatency(from, to, bins)
+    if got.Count != 100 || got.Min != 10 || got.Max != 990 {
+        t.Errorf("count/min/max = %d/%v/%v, want 100/10/990", got.Count, got.Min, got.Max)
+    }
+    if math.Abs(got.Avg-28.26) > 1e-9 {
+        t.Errorf("avg = %v, want 28.26", got.Avg)
+    }
+    if got.P50 != 10.4 || got.P95 != 104 || got.P99 != 104 {
+        t.Errorf("p50/p95/p99 = %v/%v/%v, want 10.4/104/104", got.P50, got.P95, got.P99)
+    }
+}
+
+func TestGetServiceLatencyAggregatesBuckets(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+
+    to := time.Now().Truncate(time.Minute)
+    from := to.Add(-2 * time.Hour)
+    samples := []interface{}{
+        models.LatencySample{OrganizationID: orgID, ServiceID: service.ID, LatencyMs: 20, Healthy: true, CreatedAt: from.Add(10 * time.Minute)},
+        models.LatencySample{OrganizationID: orgID, ServiceID: service.ID, LatencyMs: 40, Healthy: true, CreatedAt: from.Add(20 * time.Minute)},
+        models.LatencySample{OrganizationID: orgID, ServiceID: service.ID, LatencyMs: 200, Healthy: true, CreatedAt: from.Add(90 * time.Minute)},
+        models.LatencySample{OrganizationID: orgID, ServiceID: service.ID, LatencyMs: 9000, Healthy: false, CreatedAt: from.Add(95 * time.Minute)},
+    }
+    if _, err := database.GetCollection("latency_samples").InsertMany(context.TODO(), samples); err != nil {
+        t.Fatal(err)
+    }
+
+    r := tenantRouter(orgID, models.RoleViewer)
+    r.GET("/services/:id/latency", GetServiceLatency)
+    path := "/services/" + service.ID.Hex() + "/latency?from=" + from.Format(time.RFC3339) +
+        "&to=" + to.Format(time.RFC3339) + "&bucket_minutes=60"
+    w := doJSON(r, http.MethodGet, path, nil)
+    if w.Code != http.StatusOK {
+        t.Fatalf("latency = %d: %s", w.Code, w.Body)
+    }
+
+    var body struct {
+        Latency latencyStats   `json:"latency"`
+        Series  []latencyStats `json:"series"`
+    }
+    if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+        t.Fatal(err)
+    }
+    if body.Latency.Count != 3 || body.Latency.Min != 20 || body.Latency.Max != 200 || body.Latency.Avg != 260.0/3 {
+        t.Errorf("overall = %+v, want 3 healthy samples between 20 and 200", body.Latency)
+    }
+    if len(body.Series) != 2 {
+        t.Fatalf("series has %d buckets, want 2", len(body.Series))
+    }
+    if body.Series[0].Count != 2 || body.Series[0].P50 != 20 || body.Series[0].Max != 40 {
+        t.Errorf("first bucket = %+v", body.Series[0])
+    }
+    if body.Series[1].Count != 1 || body.Series[1].P99 != 200 {
+        t.Errorf("second bucket = %+v", body.Series[1])
+    }
+}
\ No newline at end of file
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    gorilla "github.com/gorilla/websocket"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+// setupTestDB connects to a fresh database on MONGODB_TEST_URI and drops it
+// when the test ends. Tests that need MongoDB are skipped without it.
+func setupTestDB(t *testing.T) {
+    t.Helper()
+    uri := os.Getenv("MONGODB_TEST_URI")
+    if uri == "" {
+        t.Skip("MONGODB_TEST_URI not set")
+    }
+    if err := database.ConnectDB(uri, fmt.Sprintf("statuspage_test_%d", time.Now().UnixNano())); err != nil {
+        t.Fatalf("connect: %v", err)
+    }
+    if err := database.EnsureIndexes(); err != nil {
+        t.Fatalf("indexes: %v", err)
+    }
+    db := database.DB
+    t.Cleanup(func() {
+        db.Drop(context.TODO())
+        db.Client().Disconnect(context.TODO())
+    })
+}
+
+// tenantRouter returns a router whose requests run as a member of orgID
+// with role, as TenantMiddleware would set them up
+func tenantRouter(orgID primitive.ObjectID, role models.Role) *gin.Engine {
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(func(c *gin.Context) {
+        c.Set("user_id", "test-user")
+        c.Set("organization_id", orgID.Hex())
+        c.Set("member_role", role)
+        c.Next()
+    })
+    return r
+}
+
+// listenBroadcasts starts a hub with one connected client. The returned
+// function waits for the next message of msgType and returns its data.
+func listenBroadcasts(t *testing.T) (*websocket.Hub, func(msgType string) map[string]interface{}) {
+    t.Helper()
+    hub := websocket.NewHub()
+    go hub.Run()
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/ws", hub.HandleWebSocket)
+    server := httptest.NewServer(r)
+    t.Cleanup(server.Close)
+
+    conn, _, err := gorilla.DefaultDialer.Dial("ws"+server.URL[len("http"):]+"/ws", nil)
+    if err != nil {
+        t.Fatalf("dial: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    for deadline := time.Now().Add(time.Second); hub.GetClientCount() == 0; {
+        if time.Now().After(deadline) {
+            t.Fatal("client never registered")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    return hub, func(msgType string) map[string]interface{} {
+        t.Helper()
+        conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+        for {
+            var message struct {
+                Type string                 `json:"type"`
+                Data map[string]interface{} `json:"data"`
+            }
+            if err := conn.ReadJSON(&message); err != nil {
+                t.Fatalf("waiting for %s: %v", msgType, err)
+            }
+            if message.Type == msgType {
+                return message.Data
+            }
+        }
+    }
+}
+
+// doJSON sends body as JSON and returns the recorded response
+func doJSON(r http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+    var payload []byte
+    if body != nil {
+        payload, _ = json.Marshal(body)
+    }
+    req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+    req.Header.Set("Content-Type", "application/json")
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+    return w
+}
+
+// insertService stores an operational service for orgID
+func insertService(t *testing.T, orgID primitive.ObjectID, name string) models.Service {
+    t.Helper()
+    service := models.Service{
+        ID:             primitive.NewObjectID(),
+        OrganizationID: orgID,
+        Name:           name,
+        Status:         models.StatusOperational,
+        CreatedAt:      time.Now(),
+        UpdatedAt:      time.Now(),
+    }
+    if _, err := database.GetCollection("services").InsertOne(context.TODO(), service); err != nil {
+        t.Fatalf("insert service: %v", err)
+    }
+    return service
+}
+
+// findService loads a service by ID, failing the test if it is missing
+func findService(t *testing.T, id primitive.ObjectID) models.Service {
+    t.Helper()
+    var service models.Service
+    if err := database.GetCollection("services").FindOne(context.TODO(), map[string]interface{}{"_id": id}).Decode(&service); err != nil {
+        t.Fatalf("find service: %v", err)
+    }
+    return service
+}
+
+// findIncident loads an incident by ID, failing the test if it is missing
+func findIncident(t *testing.T, id primitive.ObjectID) models.Incident {
+    t.Helper()
+    var incident models.Incident
+    if err := database.GetCollection("incidents").FindOne(context.TODO(), map[string]interface{}{"_id": id}).Decode(&incident); err != nil {
+        t.Fatalf("find incident: %v", err)
+    }
+    return incident
+}
+
+// insertIncident stores an investigating incident for orgID affecting services
+func insertIncident(t *testing.T, orgID primitive.ObjectID, title string, services ...primitive.ObjectID) models.Incident {
+    t.Helper()
+    incident := models.Incident{
+        ID:               primitive.NewObjectID(),
+        OrganizationID:   orgID,
+        Title:            title,
+        Status:           models.IncidentStatusInvestigating,
+        Type:             "incident",
+        Impact:           models.ImpactMinor,
+        AffectedServices: services,
+        CreatedAt:        time.Now(),
+        UpdatedAt:        time.Now(),
+        CreatedBy:        "test-user",
+    }
+    if _, err := database.GetCollection("incidents").InsertOne(context.TODO(), incident); err != nil {
+        t.Fatalf("insert incident: %v", err)
+    }
+    return incident
+}
+
+// insertOrganization stores an organization with the given slug and members
+func insertOrganization(t *testing.T, slug string, members ...models.Member) models.Organization {
+    t.Helper()
+    org := models.Organization{
+        ID:        primitive.NewObjectID(),
+        Name:      slug,
+        Slug:      slug,
+        Members:   members,
+        CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
+    }
+    if _, err := database.GetCollection("organizations").InsertOne(context.TODO(), org); err != nil {
+        t.Fatalf("insert organization: %v", err)
+    }
+    return org
+}
\ No newline at end of file
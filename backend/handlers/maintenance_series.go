@@ -0,0 +1,462 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// SeriesHorizon is how far ahead recurring maintenance is expanded into
+// individual windows
+const SeriesHorizon = 14 * 24 * time.Hour
+
+// maxOccurrenceDays caps how far ahead occurrences can be listed
+const maxOccurrenceDays = 90
+
+// ExpandMaintenanceSeries creates the series' windows overlapping [from, to)
+// that don't exist yet. Windows that were edited or cancelled are left alone.
+func ExpandMaintenanceSeries(series models.MaintenanceSeries, from, to time.Time) error {
+    occurrences, err := series.Occurrences(from, to)
+    if err != nil {
+        return err
+    }
+
+    collection := database.GetCollection("maintenances")
+    for _, start := range occurrences {
+        now := time.Now()
+        occurrenceStart := start
+        maintenance := models.Maintenance{
+            OrganizationID:   series.OrganizationID,
+            Title:            series.Title,
+            Description:      series.Description,
+            AffectedServices: series.AffectedServices,
+            ScheduledStart:   start,
+            ScheduledEnd:     start.Add(series.Duration()),
+            Status:           models.MaintenanceScheduled,
+            SeriesID:         &series.ID,
+            OccurrenceStart:  &occurrenceStart,
+            CreatedBy:        series.CreatedBy,
+            CreatedAt:        now,
+            UpdatedAt:        now,
+        }
+
+        _, err := collection.UpdateOne(
+            context.TODO(),
+            bson.M{"organization_id": series.OrganizationID, "series_id": series.ID, "occurrence_start": start},
+            bson.M{"$setOnInsert": maintenance},
+            options.Update().SetUpsert(true),
+        )
+        if err != nil && !mongo.IsDuplicateKeyError(err) {
+            return err
+        }
+    }
+    return nil
+}
+
+// dropFutureOccurrences retires windows of the series that haven't started
+// and weren't edited on their own, so they can be expanded again from the
+// changed series. They are soft-deleted and detached from the series, so the
+// expansion doesn't match them as cancelled occurrences.
+func dropFutureOccurrences(orgID, seriesID primitive.ObjectID, now time.Time) error {
+    _, err := database.GetCollection("maintenances").UpdateMany(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "series_id":       seriesID,
+            "status":          models.MaintenanceScheduled,
+            "scheduled_start": bson.M{"$gt": now},
+            "modified":        bson.M{"$ne": true},
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": now,
+            },
+            "$rename": bson.M{"series_id": "replaced_series_id"},
+            "$unset":  bson.M{"occurrence_start": ""},
+        },
+    )
+    return err
+}
+
+// editedOccurrences returns the upcoming windows of the series that were
+// edited on their own, which a change to the series doesn't touch
+func editedOccurrences(orgID, seriesID primitive.ObjectID, now time.Time) ([]models.Maintenance, error) {
+    cursor, err := database.GetCollection("maintenances").Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "series_id":       seriesID,
+            "status":          models.MaintenanceScheduled,
+            "scheduled_start": bson.M{"$gt": now},
+            "modified":        true,
+            "deleted":         bson.M{"$ne": true},
+        },
+        options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: 1}}),
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    occurrences := make([]models.Maintenance, 0)
+    err = cursor.All(context.TODO(), &occurrences)
+    return occurrences, err
+}
+
+func GetMaintenanceSeries(c *gin.Context) {
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    collection := database.GetCollection("maintenance_series")
+    cursor, err := collection.Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+    )
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch maintenance series"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    series := make([]models.MaintenanceSeries, 0)
+    if err := cursor.All(context.TODO(), &series); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode maintenance series"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+func CreateMaintenanceSeries(c *gin.Context) {
+    var series models.MaintenanceSeries
+    if err := c.ShouldBindJSON(&series); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    if err := series.Validate(); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    orgID := c.GetString("organization_id")
+    series.ID = primitive.NilObjectID
+    series.OrganizationID, _ = primitive.ObjectIDFromHex(orgID)
+    series.CreatedBy = c.GetString("user_id")
+    series.Deleted = false
+    series.CreatedAt = time.Now()
+    series.UpdatedAt = time.Now()
+    if series.AffectedServices == nil {
+        series.AffectedServices = make([]primitive.ObjectID, 0)
+    }
+
+    ok, err := servicesBelongTo(series.OrganizationID, series.AffectedServices)
+    if err != nil {
+        log.Printf("Error checking affected services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
+        return
+    }
+
+    result, err := database.GetCollection("maintenance_series").InsertOne(context.TODO(), series)
+    if err != nil {
+        log.Printf("Error creating maintenance series: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create maintenance series"})
+        return
+    }
+    series.ID = result.InsertedID.(primitive.ObjectID)
+
+    now := time.Now()
+    if err := ExpandMaintenanceSeries(series, now, now.Add(SeriesHorizon)); err != nil {
+        log.Printf("Error expanding maintenance series %s: %v", series.Title, err)
+    }
+
+    log.Printf("✅ Maintenance series created: %s", series.Title)
+    c.JSON(http.StatusCreated, gin.H{"series": series})
+}
+
+// UpdateMaintenanceSeries changes only the fields present in the request.
+// Upcoming windows that weren't edited on their own are regenerated, the
+// edited ones keep their own title, services and times and are returned as
+// edited_occurrences so the caller can review them.
+func UpdateMaintenanceSeries(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance series ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Title            *string               `json:"title"`
+        Description      *string               `json:"description"`
+        AffectedServices *[]primitive.ObjectID `json:"affected_services"`
+        RRule            *string               `json:"rrule"`
+        Timezone         *string               `json:"timezone"`
+        StartLocal       *string               `json:"start_local"`
+        DurationMinutes  *int                  `json:"duration_minutes"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    collection := database.GetCollection("maintenance_series")
+    filter := bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+
+    var series models.MaintenanceSeries
+    if err := collection.FindOne(context.TODO(), filter).Decode(&series); err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance series not found"})
+        } else {
+            log.Printf("Error finding maintenance series: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    if update.Title != nil {
+        series.Title = *update.Title
+    }
+    if update.Description != nil {
+        series.Description = *update.Description
+    }
+    if update.RRule != nil {
+        series.RRule = *update.RRule
+    }
+    if update.Timezone != nil {
+        series.Timezone = *update.Timezone
+    }
+    if update.StartLocal != nil {
+        series.StartLocal = *update.StartLocal
+    }
+    if update.DurationMinutes != nil {
+        series.DurationMinutes = *update.DurationMinutes
+    }
+    if update.AffectedServices != nil {
+        ok, err := servicesBelongTo(orgID, *update.AffectedServices)
+        if err != nil {
+            log.Printf("Error checking affected services: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if !ok {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
+            return
+        }
+        series.AffectedServices = *update.AffectedServices
+    }
+
+    if err := series.Validate(); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    series.UpdatedAt = time.Now()
+    _, err = collection.UpdateOne(context.TODO(), filter, bson.M{
+        "$set": bson.M{
+            "title":             series.Title,
+            "description":       series.Description,
+            "affected_services": series.AffectedServices,
+            "rrule":             series.RRule,
+            "timezone":          series.Timezone,
+            "start_local":       series.StartLocal,
+            "duration_minutes":  series.DurationMinutes,
+            "updated_at":        series.UpdatedAt,
+        },
+    })
+    if err != nil {
+        log.Printf("Error updating maintenance series: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update maintenance series"})
+        return
+    }
+
+    now := time.Now()
+    if err := dropFutureOccurrences(orgID, series.ID, now); err != nil {
+        log.Printf("Error dropping occurrences of %s: %v", series.Title, err)
+    } else if err := ExpandMaintenanceSeries(series, now, now.Add(SeriesHorizon)); err != nil {
+        log.Printf("Error expanding maintenance series %s: %v", series.Title, err)
+    }
+
+    edited, err := editedOccurrences(orgID, series.ID, now)
+    if err != nil {
+        log.Printf("Error finding edited occurrences of %s: %v", series.Title, err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch edited occurrences"})
+        return
+    }
+
+    log.Printf("✅ Maintenance series updated: %s", series.Title)
+    c.JSON(http.StatusOK, gin.H{"series": series, "edited_occurrences": edited})
+}
+
+// DeleteMaintenanceSeries soft-deletes a series and cancels its upcoming
+// windows. A window already in progress runs to its end.
+func DeleteMaintenanceSeries(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance series ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var series models.MaintenanceSeries
+    err = database.GetCollection("maintenance_series").FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    ).Decode(&series)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance series not found"})
+        } else {
+            log.Printf("Error deleting maintenance series: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete maintenance series"})
+        }
+        return
+    }
+
+    _, err = database.GetCollection("maintenances").UpdateMany(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "series_id":       objID,
+            "status":          models.MaintenanceScheduled,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    )
+    if err != nil {
+        log.Printf("Error cancelling occurrences of %s: %v", series.Title, err)
+    }
+
+    log.Printf("✅ Maintenance series deleted: %s", series.Title)
+    c.JSON(http.StatusOK, gin.H{"message": "Maintenance series deleted successfully"})
+}
+
+// GetMaintenanceSeriesOccurrences lists the series' windows for the next
+// ?days=N days, expanding them first so each can be edited or cancelled
+// through the maintenance endpoints
+func GetMaintenanceSeriesOccurrences(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance series ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    days := 30
+    if raw := c.Query("days"); raw != "" {
+        days, err = strconv.Atoi(raw)
+        if err != nil || days < 1 || days > maxOccurrenceDays {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 90"})
+            return
+        }
+    }
+
+    var series models.MaintenanceSeries
+    err = database.GetCollection("maintenance_series").FindOne(context.TODO(), bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&series)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance series not found"})
+        } else {
+            log.Printf("Error finding maintenance series: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    now := time.Now()
+    to := now.Add(time.Duration(days) * 24 * time.Hour)
+    if err := ExpandMaintenanceSeries(series, now, to); err != nil {
+        log.Printf("Error expanding maintenance series %s: %v", series.Title, err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to expand maintenance series"})
+        return
+    }
+
+    // Cancelled windows are included so they can be told apart from ones
+    // that were never scheduled
+    cursor, err := database.GetCollection("maintenances").Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "series_id":       objID,
+            "scheduled_end":   bson.M{"$gt": now},
+            "scheduled_start": bson.M{"$lt": to},
+        },
+        options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: 1}}),
+    )
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch occurrences"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    occurrences := make([]models.Maintenance, 0)
+    if err := cursor.All(context.TODO(), &occurrences); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode occurrences"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"series": series, "occurrences": occurrences})
+}
\ No newline at end of file
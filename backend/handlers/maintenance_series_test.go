@@ -0,0 +1,152 @@
+package handlers
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// seriesOccurrences returns the live windows of a series, earliest first
+func seriesOccurrences(t *testing.T, seriesID primitive.ObjectID) []models.Maintenance {
+    t.Helper()
+    cursor, err := database.GetCollection("maintenances").Find(context.TODO(),
+        bson.M{"series_id": seriesID, "deleted": bson.M{"$ne": true}},
+        options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: 1}}),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    var occurrences []models.Maintenance
+    if err := cursor.All(context.TODO(), &occurrences); err != nil {
+        t.Fatal(err)
+    }
+    return occurrences
+}
+
+func TestUpdateMaintenanceSeriesKeepsEditedAndCancelledOccurrences(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/maintenance-series", CreateMaintenanceSeries)
+    r.PATCH("/maintenance-series/:id", UpdateMaintenanceSeries)
+    r.PATCH("/maintenances/:id", UpdateMaintenance)
+    r.DELETE("/maintenances/:id", DeleteMaintenance)
+
+    tomorrow := time.Now().UTC().Add(24 * time.Hour)
+    w := doJSON(r, http.MethodPost, "/maintenance-series", map[string]interface{}{
+        "title":            "Nightly patching",
+        "rrule":            "FREQ=DAILY",
+        "timezone":         "UTC",
+        "start_local":      tomorrow.Format("2006-01-02") + "T03:00",
+        "duration_minutes": 60,
+    })
+    if w.Code != http.StatusCreated {
+        t.Fatalf("create series = %d: %s", w.Code, w.Body)
+    }
+    var created struct {
+        Series models.MaintenanceSeries `json:"series"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &created)
+    seriesID := created.Series.ID
+
+    occurrences := seriesOccurrences(t, seriesID)
+    if len(occurrences) < 3 {
+        t.Fatalf("expanded %d occurrences, want at least 3", len(occurrences))
+    }
+    edited, cancelled, regenerated := occurrences[0], occurrences[1], occurrences[2]
+
+    if w := doJSON(r, http.MethodPatch, "/maintenances/"+edited.ID.Hex(), map[string]interface{}{"title": "Patching, moved"}); w.Code != http.StatusOK {
+        t.Fatalf("edit occurrence = %d: %s", w.Code, w.Body)
+    }
+    if w := doJSON(r, http.MethodDelete, "/maintenances/"+cancelled.ID.Hex(), nil); w.Code != http.StatusOK {
+        t.Fatalf("cancel occurrence = %d: %s", w.Code, w.Body)
+    }
+
+    w = doJSON(r, http.MethodPatch, "/maintenance-series/"+seriesID.Hex(), map[string]interface{}{"title": "Weekly patching"})
+    if w.Code != http.StatusOK {
+        t.Fatalf("update series = %d: %s", w.Code, w.Body)
+    }
+    var updated struct {
+        EditedOccurrences []models.Maintenance `json:"edited_occurrences"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &updated)
+    if len(updated.EditedOccurrences) != 1 || updated.EditedOccurrences[0].ID != edited.ID {
+        t.Errorf("edited_occurrences = %+v, want only the edited window", updated.EditedOccurrences)
+    }
+
+    after := seriesOccurrences(t, seriesID)
+    byStart := make(map[time.Time]models.Maintenance)
+    for _, occurrence := range after {
+        byStart[occurrence.OccurrenceStart.UTC()] = occurrence
+    }
+    if got := byStart[edited.OccurrenceStart.UTC()]; got.ID != edited.ID || got.Title != "Patching, moved" {
+        t.Errorf("edited occurrence = %+v, want it kept with its own title", got)
+    }
+    if got, ok := byStart[cancelled.OccurrenceStart.UTC()]; ok {
+        t.Errorf("cancelled occurrence came back as %+v", got)
+    }
+    if got := byStart[regenerated.OccurrenceStart.UTC()]; got.ID == regenerated.ID || got.Title != "Weekly patching" {
+        t.Errorf("regenerated occurrence = %+v, want a new window with the new title", got)
+    }
+
+    // The replaced window is kept, soft-deleted and detached from the series
+    var replaced bson.M
+    err := database.GetCollection("maintenances").FindOne(context.TODO(), bson.M{"_id": regenerated.ID}).Decode(&replaced)
+    if err != nil {
+        t.Fatalf("replaced occurrence was removed: %v", err)
+    }
+    if replaced["deleted"] != true || replaced["replaced_series_id"] != seriesID || replaced["series_id"] != nil {
+        t.Errorf("replaced occurrence = %v, want deleted with replaced_series_id", replaced)
+    }
+}
+
+func TestMaintenanceSeriesIgnoresOtherOrganizationsWindows(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.POST("/maintenance-series", CreateMaintenanceSeries)
+    r.DELETE("/maintenance-series/:id", DeleteMaintenanceSeries)
+
+    tomorrow := time.Now().UTC().Add(24 * time.Hour)
+    w := doJSON(r, http.MethodPost, "/maintenance-series", map[string]interface{}{
+        "title":            "Nightly patching",
+        "rrule":            "FREQ=DAILY",
+        "start_local":      tomorrow.Format("2006-01-02") + "T03:00",
+        "duration_minutes": 60,
+    })
+    if w.Code != http.StatusCreated {
+        t.Fatalf("create series = %d: %s", w.Code, w.Body)
+    }
+    var created struct {
+        Series models.MaintenanceSeries `json:"series"`
+    }
+    json.Unmarshal(w.Body.Bytes(), &created)
+
+    // A window of another organization claiming to belong to the series
+    foreign := insertMaintenance(t, primitive.NewObjectID(), "Not yours")
+    _, err := database.GetCollection("maintenances").UpdateOne(context.TODO(),
+        bson.M{"_id": foreign.ID},
+        bson.M{"$set": bson.M{"series_id": created.Series.ID}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if w := doJSON(r, http.MethodDelete, "/maintenance-series/"+created.Series.ID.Hex(), nil); w.Code != http.StatusOK {
+        t.Fatalf("delete series = %d: %s", w.Code, w.Body)
+    }
+    var stored models.Maintenance
+    database.GetCollection("maintenances").FindOne(context.TODO(), bson.M{"_id": foreign.ID}).Decode(&stored)
+    if stored.Deleted {
+        t.Error("deleting the series cancelled another organization's window")
+    }
+}
\ No newline at end of file
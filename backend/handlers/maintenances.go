@@ -0,0 +1,581 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+func GetMaintenances(c *gin.Context) {
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    filter := bson.M{
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+    if status := c.Query("status"); status != "" {
+        filter["status"] = status
+    }
+
+    collection := database.GetCollection("maintenances")
+    cursor, err := collection.Find(
+        context.TODO(),
+        filter,
+        options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: -1}}),
+    )
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch maintenances"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    maintenances := make([]models.Maintenance, 0)
+    if err := cursor.All(context.TODO(), &maintenances); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode maintenances"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"maintenances": maintenances})
+}
+
+// createMaintenanceRequest is the body of POST /maintenances. Status, series
+// membership and everything the scheduler records are set by the server.
+type createMaintenanceRequest struct {
+    Title            string               `json:"title"`
+    Description      string               `json:"description"`
+    AffectedServices []primitive.ObjectID `json:"affected_services"`
+    ScheduledStart   time.Time            `json:"scheduled_start"`
+    ScheduledEnd     time.Time            `json:"scheduled_end"`
+}
+
+func (r createMaintenanceRequest) maintenance() models.Maintenance {
+    return models.Maintenance{
+        Title:            r.Title,
+        Description:      r.Description,
+        AffectedServices: r.AffectedServices,
+        ScheduledStart:   r.ScheduledStart,
+        ScheduledEnd:     r.ScheduledEnd,
+    }
+}
+
+func CreateMaintenance(c *gin.Context) {
+    var request createMaintenanceRequest
+    if err := c.ShouldBindJSON(&request); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    maintenance := request.maintenance()
+
+    if maintenance.Title == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Title is required"})
+        return
+    }
+    if !maintenance.ScheduledEnd.After(maintenance.ScheduledStart) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_end must be after scheduled_start"})
+        return
+    }
+
+    orgID := c.GetString("organization_id")
+    maintenance.OrganizationID, _ = primitive.ObjectIDFromHex(orgID)
+    maintenance.Status = models.MaintenanceScheduled
+    maintenance.CreatedBy = c.GetString("user_id")
+    maintenance.CreatedAt = time.Now()
+    maintenance.UpdatedAt = time.Now()
+    if maintenance.AffectedServices == nil {
+        maintenance.AffectedServices = make([]primitive.ObjectID, 0)
+    }
+
+    ok, err := servicesBelongTo(maintenance.OrganizationID, maintenance.AffectedServices)
+    if err != nil {
+        log.Printf("Error checking affected services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
+        return
+    }
+
+    collection := database.GetCollection("maintenances")
+    result, err := collection.InsertOne(context.TODO(), maintenance)
+    if err != nil {
+        log.Printf("Error creating maintenance: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create maintenance"})
+        return
+    }
+
+    maintenance.ID = result.InsertedID.(primitive.ObjectID)
+    broadcastMaintenance(websocketHub(c), "maintenance_created", maintenance)
+
+    log.Printf("✅ Maintenance scheduled: %s", maintenance.Title)
+    c.JSON(http.StatusCreated, gin.H{"maintenance": maintenance})
+}
+
+// UpdateMaintenance changes only the fields present in the request. Once a
+// window has started only its title, description and end can change; moving
+// the end into the past finishes it early.
+func UpdateMaintenance(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Title            *string               `json:"title"`
+        Description      *string               `json:"description"`
+        AffectedServices *[]primitive.ObjectID `json:"affected_services"`
+        ScheduledStart   *time.Time            `json:"scheduled_start"`
+        ScheduledEnd     *time.Time            `json:"scheduled_end"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    collection := database.GetCollection("maintenances")
+    filter := bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+
+    var existing models.Maintenance
+    if err := collection.FindOne(context.TODO(), filter).Decode(&existing); err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance not found"})
+        } else {
+            log.Printf("Error finding maintenance: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    if existing.Status == models.MaintenanceCompleted {
+        c.JSON(http.StatusConflict, gin.H{"error": "Completed maintenance cannot be changed"})
+        return
+    }
+    if existing.Status == models.MaintenanceInProgress && (update.AffectedServices != nil || update.ScheduledStart != nil) {
+        c.JSON(http.StatusConflict, gin.H{"error": "Affected services and start cannot change once maintenance has started"})
+        return
+    }
+
+    set := bson.M{"updated_at": time.Now()}
+    if update.Title != nil {
+        if *update.Title == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+            return
+        }
+        set["title"] = *update.Title
+    }
+    if update.Description != nil {
+        set["description"] = *update.Description
+    }
+    if update.AffectedServices != nil {
+        ok, err := servicesBelongTo(orgID, *update.AffectedServices)
+        if err != nil {
+            log.Printf("Error checking affected services: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if !ok {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Affected service not found"})
+            return
+        }
+        set["affected_services"] = *update.AffectedServices
+    }
+
+    start, end := existing.ScheduledStart, existing.ScheduledEnd
+    if update.ScheduledStart != nil {
+        start = *update.ScheduledStart
+        set["scheduled_start"] = start
+    }
+    if update.ScheduledEnd != nil {
+        end = *update.ScheduledEnd
+        set["scheduled_end"] = end
+    }
+    if !end.After(start) {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_end must be after scheduled_start"})
+        return
+    }
+
+    // Keep an edited occurrence when its series is regenerated
+    if existing.SeriesID != nil {
+        set["modified"] = true
+    }
+
+    // Only apply the change if the scheduler hasn't moved the window on
+    filter["status"] = existing.Status
+
+    var maintenance models.Maintenance
+    err = collection.FindOneAndUpdate(
+        context.TODO(),
+        filter,
+        bson.M{"$set": set},
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&maintenance)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusConflict, gin.H{"error": "Maintenance changed status, try again"})
+        } else {
+            log.Printf("Error updating maintenance: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update maintenance"})
+        }
+        return
+    }
+
+    broadcastMaintenance(websocketHub(c), "maintenance_updated", maintenance)
+
+    log.Printf("✅ Maintenance updated: %s", maintenance.Title)
+    c.JSON(http.StatusOK, gin.H{"maintenance": maintenance})
+}
+
+// DeleteMaintenance soft-deletes a window. Deleting one in progress puts its
+// services back straight away. For an occurrence of a series this cancels
+// just that occurrence.
+func DeleteMaintenance(c *gin.Context) {
+    maintenanceID := c.Param("id")
+    objID, err := primitive.ObjectIDFromHex(maintenanceID)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maintenance ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    collection := database.GetCollection("maintenances")
+    var maintenance models.Maintenance
+    err = collection.FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    ).Decode(&maintenance)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance not found"})
+        } else {
+            log.Printf("Error deleting maintenance: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete maintenance"})
+        }
+        return
+    }
+
+    if maintenance.Status == models.MaintenanceInProgress {
+        restoreMaintenanceServices(websocketHub(c), maintenance)
+    }
+
+    broadcastMaintenance(websocketHub(c), "maintenance_deleted", maintenance)
+
+    log.Printf("✅ Maintenance deleted: %s", maintenance.Title)
+    c.JSON(http.StatusOK, gin.H{"message": "Maintenance deleted successfully"})
+}
+
+// StartMaintenance moves a scheduled window in progress and puts its services
+// into maintenance. A window whose end has already passed is completed
+// without touching the services.
+func StartMaintenance(hub *websocket.Hub, maintenanceID primitive.ObjectID) error {
+    now := time.Now()
+    collection := database.GetCollection("maintenances")
+    filter := bson.M{
+        "_id":     maintenanceID,
+        "status":  models.MaintenanceScheduled,
+        "deleted": bson.M{"$ne": true},
+    }
+
+    var scheduled models.Maintenance
+    err := collection.FindOne(context.TODO(), filter).Decode(&scheduled)
+    if err == mongo.ErrNoDocuments {
+        // Someone else got there first
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    // What the services go back to is stored by the same write that starts
+    // the window, so a window in progress always knows how to end
+    targets := make([]models.ServiceImpact, 0, len(scheduled.AffectedServices))
+    if scheduled.ScheduledEnd.After(now) {
+        if targets, err = maintenanceTargets(scheduled); err != nil {
+            return err
+        }
+    }
+
+    var maintenance models.Maintenance
+    err = collection.FindOneAndUpdate(
+        context.TODO(),
+        filter,
+        bson.M{
+            "$set": bson.M{
+                "status":           models.MaintenanceInProgress,
+                "service_statuses": targets,
+                "started_at":       now,
+                "updated_at":       now,
+            },
+        },
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&maintenance)
+    if err == mongo.ErrNoDocuments {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    if !maintenance.ScheduledEnd.After(now) {
+        return CompleteMaintenance(hub, maintenance.ID)
+    }
+
+    message := "Scheduled maintenance: " + maintenance.Title
+    for i, target := range maintenance.ServiceStatuses {
+        service, err := ApplyServiceStatus(hub, maintenance.OrganizationID, target.ServiceID, models.StatusMaintenance, message, models.ActorSystem)
+        if err != nil {
+            log.Printf("Error starting maintenance on %s: %v", target.ServiceID.Hex(), err)
+            continue
+        }
+        if service.Status == target.PreviousStatus {
+            continue
+        }
+
+        // The service changed status since it was read
+        maintenance.ServiceStatuses[i].PreviousStatus = service.Status
+        _, err = collection.UpdateOne(
+            context.TODO(),
+            bson.M{"_id": maintenance.ID, "service_statuses.service_id": target.ServiceID},
+            bson.M{"$set": bson.M{"service_statuses.$.previous_status": service.Status}},
+        )
+        if err != nil {
+            log.Printf("Error storing previous status of %s: %v", target.ServiceID.Hex(), err)
+        }
+    }
+
+    broadcastMaintenance(hub, "maintenance_started", maintenance)
+    log.Printf("🔧 Maintenance started: %s", maintenance.Title)
+    return nil
+}
+
+// maintenanceTargets reads the current status of a window's services, which
+// is what they return to when it ends
+func maintenanceTargets(maintenance models.Maintenance) ([]models.ServiceImpact, error) {
+    cursor, err := database.GetCollection("services").Find(context.TODO(), bson.M{
+        "_id":             bson.M{"$in": maintenance.AffectedServices},
+        "organization_id": maintenance.OrganizationID,
+        "deleted":         bson.M{"$ne": true},
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    var services []models.Service
+    if err := cursor.All(context.TODO(), &services); err != nil {
+        return nil, err
+    }
+    current := make(map[primitive.ObjectID]models.ServiceStatus, len(services))
+    for _, service := range services {
+        current[service.ID] = service.Status
+    }
+
+    targets := make([]models.ServiceImpact, 0, len(maintenance.AffectedServices))
+    for _, serviceID := range maintenance.AffectedServices {
+        if status, ok := current[serviceID]; ok {
+            targets = append(targets, models.ServiceImpact{
+                ServiceID:      serviceID,
+                Status:         models.StatusMaintenance,
+                PreviousStatus: status,
+            })
+        }
+    }
+    return targets, nil
+}
+
+// CompleteMaintenance finishes a window in progress and hands its services
+// back the statuses they had before it started
+func CompleteMaintenance(hub *websocket.Hub, maintenanceID primitive.ObjectID) error {
+    now := time.Now()
+
+    var maintenance models.Maintenance
+    err := database.GetCollection("maintenances").FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "_id":     maintenanceID,
+            "status":  models.MaintenanceInProgress,
+            "deleted": bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "status":       models.MaintenanceCompleted,
+                "completed_at": now,
+                "updated_at":   now,
+            },
+        },
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&maintenance)
+    if err == mongo.ErrNoDocuments {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    restoreMaintenanceServices(hub, maintenance)
+
+    broadcastMaintenance(hub, "maintenance_completed", maintenance)
+    log.Printf("🔧 Maintenance completed: %s", maintenance.Title)
+    return nil
+}
+
+// restoreMaintenanceServices puts services back to their status before the
+// window, unless someone has moved them out of maintenance since. Services
+// another window in progress still covers stay in maintenance.
+func restoreMaintenanceServices(hub *websocket.Hub, maintenance models.Maintenance) {
+    message := "Maintenance completed: " + maintenance.Title
+    for _, target := range maintenance.ServiceStatuses {
+        if target.PreviousStatus == "" || target.PreviousStatus == models.StatusMaintenance {
+            continue
+        }
+
+        handedOver, err := handOverMaintenance(maintenance, target)
+        if err != nil {
+            log.Printf("Error checking other maintenance on %s: %v", target.ServiceID.Hex(), err)
+            continue
+        }
+        if handedOver {
+            continue
+        }
+
+        _, err = ReplaceServiceStatus(hub, maintenance.OrganizationID, target.ServiceID, models.StatusMaintenance, target.PreviousStatus, message, models.ActorSystem)
+        if err != nil && err != mongo.ErrNoDocuments {
+            log.Printf("Error ending maintenance on %s: %v", target.ServiceID.Hex(), err)
+        }
+    }
+}
+
+// handOverMaintenance reports whether another window in progress covers the
+// service. That window started after this one and recorded maintenance as
+// the previous status, so it takes over the status to go back to.
+func handOverMaintenance(maintenance models.Maintenance, target models.ServiceImpact) (bool, error) {
+    result, err := database.GetCollection("maintenances").UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":                         bson.M{"$ne": maintenance.ID},
+            "organization_id":             maintenance.OrganizationID,
+            "service_statuses.service_id": target.ServiceID,
+            "status":                      models.MaintenanceInProgress,
+            "deleted":                     bson.M{"$ne": true},
+        },
+        bson.M{"$set": bson.M{"service_statuses.$[target].previous_status": target.PreviousStatus}},
+        options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{
+            "target.service_id":      target.ServiceID,
+            "target.previous_status": models.StatusMaintenance,
+        }}}),
+    )
+    if err != nil {
+        return false, err
+    }
+    return result.MatchedCount > 0, nil
+}
+
+// findPublicMaintenances returns the organization's windows in progress and
+// those still to come, soonest first
+func findPublicMaintenances(orgID primitive.ObjectID) ([]models.Maintenance, []models.Maintenance, error) {
+    cursor, err := database.GetCollection("maintenances").Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+            "status":          bson.M{"$in": []models.MaintenanceStatus{models.MaintenanceScheduled, models.MaintenanceInProgress}},
+        },
+        options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: 1}}).SetLimit(50),
+    )
+    if err != nil {
+        return nil, nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    var maintenances []models.Maintenance
+    if err := cursor.All(context.TODO(), &maintenances); err != nil {
+        return nil, nil, err
+    }
+
+    active := make([]models.Maintenance, 0)
+    upcoming := make([]models.Maintenance, 0)
+    for _, maintenance := range maintenances {
+        // What was restored and which series a window belongs to are internal
+        maintenance.ServiceStatuses = nil
+        maintenance.SeriesID = nil
+        maintenance.OccurrenceStart = nil
+        maintenance.Modified = false
+        if maintenance.Status == models.MaintenanceInProgress {
+            active = append(active, maintenance)
+        } else {
+            upcoming = append(upcoming, maintenance)
+        }
+    }
+    return active, upcoming, nil
+}
+
+func broadcastMaintenance(hub *websocket.Hub, event string, maintenance models.Maintenance) {
+    websocketMessage := websocket.Message{
+        Type: event,
+        Data: map[string]interface{}{
+            "maintenance_id":     maintenance.ID.Hex(),
+            "maintenance_title":  maintenance.Title,
+            "maintenance_desc":   maintenance.Description,
+            "maintenance_status": string(maintenance.Status),
+            "scheduled_start":    maintenance.ScheduledStart.Unix(),
+            "scheduled_end":      maintenance.ScheduledEnd.Unix(),
+            "organization_id":    maintenance.OrganizationID.Hex(),
+            "affected_services":  maintenance.AffectedServices,
+            "action":             event,
+            "timestamp":          time.Now().Unix(),
+        },
+    }
+    Broadcast(hub, websocketMessage)
+}
+
+// InMaintenanceWindow reports whether a window in progress covers the service
+func InMaintenanceWindow(orgID, serviceID primitive.ObjectID) (bool, error) {
+    count, err := database.GetCollection("maintenances").CountDocuments(context.TODO(), bson.M{
+        "organization_id":   orgID,
+        "affected_services": serviceID,
+        "status":            models.MaintenanceInProgress,
+        "deleted":           bson.M{"$ne": true},
+    })
+    return count > 0, err
+}
\ No newline at end of file
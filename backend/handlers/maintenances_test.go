@@ -0,0 +1,161 @@
+package handlers
+
+import (
+    "context"
+    "encoding/json"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// insertMaintenance stores a scheduled window that is due now
+func insertMaintenance(t *testing.T, orgID primitive.ObjectID, title string, services ...primitive.ObjectID) models.Maintenance {
+    t.Helper()
+    now := time.Now()
+    maintenance := models.Maintenance{
+        ID:               primitive.NewObjectID(),
+        OrganizationID:   orgID,
+        Title:            title,
+        AffectedServices: services,
+        ScheduledStart:   now.Add(-time.Minute),
+        ScheduledEnd:     now.Add(time.Hour),
+        Status:           models.MaintenanceScheduled,
+        CreatedAt:        now,
+        UpdatedAt:        now,
+    }
+    if _, err := database.GetCollection("maintenances").InsertOne(context.TODO(), maintenance); err != nil {
+        t.Fatalf("insert maintenance: %v", err)
+    }
+    return maintenance
+}
+
+func TestCreateMaintenanceRequestDropsServerFields(t *testing.T) {
+    body := `{
+        "title": "Database upgrade",
+        "scheduled_start": "2024-03-01T02:00:00Z",
+        "scheduled_end": "2024-03-01T04:00:00Z",
+        "status": "completed",
+        "series_id": "64b7f0c2a1b2c3d4e5f60718",
+        "occurrence_start": "2024-03-01T02:00:00Z",
+        "modified": true,
+        "service_statuses": [{"service_id": "64b7f0c2a1b2c3d4e5f60719", "status": "operational"}]
+    }`
+    var request createMaintenanceRequest
+    if err := json.Unmarshal([]byte(body), &request); err != nil {
+        t.Fatal(err)
+    }
+    maintenance := request.maintenance()
+
+    if maintenance.Title != "Database upgrade" || maintenance.ScheduledEnd.Sub(maintenance.ScheduledStart) != 2*time.Hour {
+        t.Errorf("fields lost: %+v", maintenance)
+    }
+    if maintenance.SeriesID != nil || maintenance.OccurrenceStart != nil || maintenance.Modified {
+        t.Errorf("series membership taken from the body: %+v", maintenance)
+    }
+    if maintenance.Status != "" || maintenance.ServiceStatuses != nil {
+        t.Errorf("scheduler fields taken from the body: %+v", maintenance)
+    }
+}
+
+func TestPublicMaintenancesHideSeries(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    window := insertMaintenance(t, orgID, "Nightly patching")
+    seriesID := primitive.NewObjectID()
+    _, err := database.GetCollection("maintenances").UpdateOne(context.TODO(),
+        bson.M{"_id": window.ID},
+        bson.M{"$set": bson.M{"series_id": seriesID, "occurrence_start": window.ScheduledStart, "modified": true}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    _, upcoming, err := findPublicMaintenances(orgID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(upcoming) != 1 {
+        t.Fatalf("upcoming = %d windows, want 1", len(upcoming))
+    }
+    payload, _ := json.Marshal(upcoming[0])
+    var fields map[string]interface{}
+    json.Unmarshal(payload, &fields)
+    for _, field := range []string{"series_id", "occurrence_start", "modified", "service_statuses"} {
+        if _, ok := fields[field]; ok {
+            t.Errorf("public maintenance exposes %s: %s", field, payload)
+        }
+    }
+}
+
+func TestStartMaintenanceStoresPreviousStatuses(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+    if _, err := ApplyServiceStatus(nil, orgID, service.ID, models.StatusDegradedPerf, "", "test-user"); err != nil {
+        t.Fatal(err)
+    }
+    window := insertMaintenance(t, orgID, "Database upgrade", service.ID)
+
+    if err := StartMaintenance(nil, window.ID); err != nil {
+        t.Fatal(err)
+    }
+
+    var stored models.Maintenance
+    database.GetCollection("maintenances").FindOne(context.TODO(), bson.M{"_id": window.ID}).Decode(&stored)
+    if stored.Status != models.MaintenanceInProgress {
+        t.Fatalf("status = %s, want in_progress", stored.Status)
+    }
+    if len(stored.ServiceStatuses) != 1 || stored.ServiceStatuses[0].PreviousStatus != models.StatusDegradedPerf {
+        t.Errorf("service statuses = %+v, want degraded_performance recorded", stored.ServiceStatuses)
+    }
+    if got := findService(t, service.ID).Status; got != models.StatusMaintenance {
+        t.Errorf("service status = %s, want maintenance", got)
+    }
+}
+
+// A service stays in maintenance until the last window covering it ends,
+// whichever order the windows end in
+func TestOverlappingMaintenanceWindows(t *testing.T) {
+    tests := []struct {
+        name       string
+        firstToEnd int
+    }{
+        {"earlier window ends first", 0},
+        {"later window ends first", 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            setupTestDB(t)
+            orgID := primitive.NewObjectID()
+            service := insertService(t, orgID, "API")
+            windows := []models.Maintenance{
+                insertMaintenance(t, orgID, "Database upgrade", service.ID),
+                insertMaintenance(t, orgID, "Network upgrade", service.ID),
+            }
+            for _, window := range windows {
+                if err := StartMaintenance(nil, window.ID); err != nil {
+                    t.Fatal(err)
+                }
+            }
+
+            if err := CompleteMaintenance(nil, windows[tt.firstToEnd].ID); err != nil {
+                t.Fatal(err)
+            }
+            if got := findService(t, service.ID).Status; got != models.StatusMaintenance {
+                t.Fatalf("status with one window left = %s, want maintenance", got)
+            }
+
+            if err := CompleteMaintenance(nil, windows[1-tt.firstToEnd].ID); err != nil {
+                t.Fatal(err)
+            }
+            if got := findService(t, service.ID).Status; got != models.StatusOperational {
+                t.Errorf("status after both windows = %s, want operational", got)
+            }
+        })
+    }
+}
\ No newline at end of file
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+var (
+    inviteSecret []byte
+    inviteTTL    = 7 * 24 * time.Hour
+)
+
+var errInvalidInviteToken = errors.New("invalid invite token")
+
+// ConfigureInvites sets the key used to sign invite tokens and how long they
+// stay valid. Without a secret a random one is used, so outstanding invites
+// stop working when the server restarts.
+func ConfigureInvites(secret []byte, ttl time.Duration) {
+    if len(secret) == 0 {
+        log.Println("INVITE_SECRET not set, using a random invite signing key")
+        secret = make([]byte, 32)
+        if _, err := rand.Read(secret); err != nil {
+            panic(fmt.Sprintf("failed to generate invite secret: %v", err))
+        }
+    }
+    inviteSecret = secret
+    inviteTTL = ttl
+}
+
+// Invite tokens have the form <invite id>.<expiry unix>.<signature>
+func signInvite(inviteID primitive.ObjectID, expiresAt time.Time) string {
+    payload := inviteID.Hex() + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+    mac := hmac.New(sha256.New, inviteSecret)
+    mac.Write([]byte(payload))
+    return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func verifyInviteToken(token string) (primitive.ObjectID, error) {
+    parts := strings.Split(token, ".")
+    if len(parts) != 3 {
+        return primitive.NilObjectID, errInvalidInviteToken
+    }
+
+    inviteID, err := primitive.ObjectIDFromHex(parts[0])
+    if err != nil {
+        return primitive.NilObjectID, errInvalidInviteToken
+    }
+    expiry, err := strconv.ParseInt(parts[1], 10, 64)
+    if err != nil {
+        return primitive.NilObjectID, errInvalidInviteToken
+    }
+
+    if !hmac.Equal([]byte(signInvite(inviteID, time.Unix(expiry, 0))), []byte(token)) {
+        return primitive.NilObjectID, errInvalidInviteToken
+    }
+    if time.Now().After(time.Unix(expiry, 0)) {
+        return primitive.NilObjectID, errors.New("invite has expired")
+    }
+
+    return inviteID, nil
+}
+
+func GetMembers(c *gin.Context) {
+    org := currentOrganization(c)
+
+    members := org.Members
+    if members == nil {
+        members = make([]models.Member, 0)
+    }
+
+    c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+func CreateInvite(c *gin.Context) {
+    var req struct {
+        Email string      `json:"email" binding:"required,email"`
+        Role  models.Role `json:"role" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    if !req.Role.Valid() {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + string(req.Role)})
+        return
+    }
+    if req.Role == models.RoleOwner && currentRole(c) != models.RoleOwner {
+        c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can invite owners"})
+        return
+    }
+
+    org := currentOrganization(c)
+    email := strings.ToLower(strings.TrimSpace(req.Email))
+    for _, m := range org.Members {
+        if strings.EqualFold(m.Email, email) {
+            c.JSON(http.StatusConflict, gin.H{"error": "User is already a member"})
+            return
+        }
+    }
+
+    invite := models.Invite{
+        ID:             primitive.NewObjectID(),
+        OrganizationID: org.ID,
+        Email:          email,
+        Role:           req.Role,
+        InvitedBy:      c.GetString("user_id"),
+        ExpiresAt:      time.Now().Add(inviteTTL).Truncate(time.Second),
+        CreatedAt:      time.Now(),
+    }
+    token := signInvite(invite.ID, invite.ExpiresAt)
+    invite.TokenHash = hashToken(token)
+
+    collection := database.GetCollection("invites")
+    if _, err := collection.InsertOne(context.TODO(), invite); err != nil {
+        log.Printf("Error creating invite: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+        return
+    }
+
+    log.Printf("✅ Invite created for %s in %s", invite.Email, org.Name)
+    c.JSON(http.StatusCreated, gin.H{"invite": invite, "token": token})
+}
+
+func AcceptInvite(c *gin.Context) {
+    var req struct {
+        Token string `json:"token" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    inviteID, err := verifyInviteToken(req.Token)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    userID := c.GetString("user_id")
+    userEmail := c.GetString("user_email")
+
+    invitesCollection := database.GetCollection("invites")
+    var invite models.Invite
+    err = invitesCollection.FindOne(context.TODO(), bson.M{
+        "_id":        inviteID,
+        "token_hash": hashToken(req.Token),
+    }).Decode(&invite)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+        } else {
+            log.Printf("Error finding invite: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    // Whoever holds the link must also own the invited address
+    if userEmail == "" || !c.GetBool("user_email_verified") {
+        c.JSON(http.StatusForbidden, gin.H{"error": "A verified email is required to accept invites"})
+        return
+    }
+    if !strings.EqualFold(userEmail, invite.Email) {
+        c.JSON(http.StatusForbidden, gin.H{"error": "Invite was issued to a different email"})
+        return
+    }
+
+    // Claim the invite first so it can only be used once
+    now := time.Now()
+    result, err := invitesCollection.UpdateOne(
+        context.TODO(),
+        bson.M{"_id": invite.ID, "accepted_at": bson.M{"$exists": false}},
+        bson.M{"$set": bson.M{"accepted_at": now, "accepted_by": userID}},
+    )
+    if err != nil {
+        log.Printf("Error accepting invite: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invite"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusConflict, gin.H{"error": "Invite has already been used"})
+        return
+    }
+
+    member := models.Member{UserID: userID, Role: invite.Role, Email: userEmail}
+
+    orgCollection := database.GetCollection("organizations")
+    result, err = orgCollection.UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":             invite.OrganizationID,
+            "deleted":         bson.M{"$ne": true},
+            "members.user_id": bson.M{"$ne": userID},
+        },
+        bson.M{
+            "$push": bson.M{"members": member},
+            "$set":  bson.M{"updated_at": now},
+        },
+    )
+    if err != nil {
+        log.Printf("Error adding member: %v", err)
+        releaseInvite(invite.ID, userID, now)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join organization"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        releaseInvite(invite.ID, userID, now)
+        c.JSON(http.StatusConflict, gin.H{"error": "Already a member or organization no longer exists"})
+        return
+    }
+
+    log.Printf("✅ %s joined organization %s as %s", userID, invite.OrganizationID.Hex(), invite.Role)
+    c.JSON(http.StatusOK, gin.H{"organization_id": invite.OrganizationID, "member": member})
+}
+
+// releaseInvite undoes a claim made by AcceptInvite when joining failed, so
+// the invite can still be used
+func releaseInvite(inviteID primitive.ObjectID, userID string, claimedAt time.Time) {
+    _, err := database.GetCollection("invites").UpdateOne(
+        context.TODO(),
+        bson.M{"_id": inviteID, "accepted_by": userID, "accepted_at": claimedAt},
+        bson.M{"$unset": bson.M{"accepted_at": "", "accepted_by": ""}},
+    )
+    if err != nil {
+        log.Printf("Error releasing invite %s: %v", inviteID.Hex(), err)
+    }
+}
+
+func UpdateMemberRole(c *gin.Context) {
+    var req struct {
+        Role models.Role `json:"role" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    if !req.Role.Valid() {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + string(req.Role)})
+        return
+    }
+
+    org := currentOrganization(c)
+    target, ok := org.FindMember(c.Param("user_id"))
+    if !ok {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+        return
+    }
+
+    if (target.Role == models.RoleOwner || req.Role == models.RoleOwner) && currentRole(c) != models.RoleOwner {
+        c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can change owner roles"})
+        return
+    }
+
+    filter := bson.M{"_id": org.ID}
+    if target.Role == models.RoleOwner && req.Role != models.RoleOwner {
+        filter["members"] = otherOwnerMatch(target.UserID)
+    }
+
+    collection := database.GetCollection("organizations")
+    result, err := collection.UpdateOne(
+        context.TODO(),
+        filter,
+        bson.M{"$set": bson.M{
+            "members.$[m].role": req.Role,
+            "updated_at":        time.Now(),
+        }},
+        options.Update().SetArrayFilters(options.ArrayFilters{
+            Filters: []interface{}{bson.M{"m.user_id": target.UserID}},
+        }),
+    )
+    if err != nil {
+        log.Printf("Error updating member role: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update member"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusConflict, gin.H{"error": "Organization must keep at least one owner"})
+        return
+    }
+
+    target.Role = req.Role
+    log.Printf("✅ Member %s role changed to %s", target.UserID, target.Role)
+    c.JSON(http.StatusOK, gin.H{"member": target})
+}
+
+func RemoveMember(c *gin.Context) {
+    org := currentOrganization(c)
+    target, ok := org.FindMember(c.Param("user_id"))
+    if !ok {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+        return
+    }
+
+    if target.Role == models.RoleOwner && currentRole(c) != models.RoleOwner {
+        c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can remove owners"})
+        return
+    }
+
+    filter := bson.M{"_id": org.ID}
+    if target.Role == models.RoleOwner {
+        filter["members"] = otherOwnerMatch(target.UserID)
+    }
+
+    collection := database.GetCollection("organizations")
+    result, err := collection.UpdateOne(
+        context.TODO(),
+        filter,
+        bson.M{
+            "$pull": bson.M{"members": bson.M{"user_id": target.UserID}},
+            "$set":  bson.M{"updated_at": time.Now()},
+        },
+    )
+    if err != nil {
+        log.Printf("Error removing member: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+        return
+    }
+    if result.MatchedCount == 0 {
+        c.JSON(http.StatusConflict, gin.H{"error": "Organization must keep at least one owner"})
+        return
+    }
+
+    log.Printf("✅ Member %s removed from %s", target.UserID, org.Name)
+    c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// otherOwnerMatch matches organizations that have an owner besides userID, so
+// demoting or removing an owner is atomic with the "at least one owner" rule
+func otherOwnerMatch(userID string) bson.M {
+    return bson.M{"$elemMatch": bson.M{
+        "role":    models.RoleOwner,
+        "user_id": bson.M{"$ne": userID},
+    }}
+}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// insertInvite stores an invite to orgID and returns its token
+func insertInvite(t *testing.T, orgID primitive.ObjectID, email string) (models.Invite, string) {
+    t.Helper()
+    ConfigureInvites([]byte("test-secret"), time.Hour)
+    invite := models.Invite{
+        ID:             primitive.NewObjectID(),
+        OrganizationID: orgID,
+        Email:          email,
+        Role:           models.RoleEditor,
+        ExpiresAt:      time.Now().Add(time.Hour),
+        CreatedAt:      time.Now(),
+    }
+    token := signInvite(invite.ID, invite.ExpiresAt)
+    invite.TokenHash = hashToken(token)
+    if _, err := database.GetCollection("invites").InsertOne(context.TODO(), invite); err != nil {
+        t.Fatalf("insert invite: %v", err)
+    }
+    return invite, token
+}
+
+// inviteeRouter runs requests as test-user signed in with email
+func inviteeRouter(email string, verified bool) *gin.Engine {
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(func(c *gin.Context) {
+        c.Set("user_id", "test-user")
+        c.Set("user_email", email)
+        c.Set("user_email_verified", verified)
+        c.Next()
+    })
+    r.POST("/invites/accept", AcceptInvite)
+    return r
+}
+
+func TestAcceptInviteReleasesClaimWhenJoiningFails(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme", models.Member{UserID: "test-user", Role: models.RoleViewer, Email: "me@example.com"})
+    invite, token := insertInvite(t, org.ID, "me@example.com")
+
+    r := inviteeRouter("me@example.com", true)
+
+    // Already a member, so the invite isn't used up
+    w := doJSON(r, http.MethodPost, "/invites/accept", map[string]interface{}{"token": token})
+    if w.Code != http.StatusConflict {
+        t.Fatalf("status = %d, want 409: %s", w.Code, w.Body)
+    }
+
+    var stored models.Invite
+    database.GetCollection("invites").FindOne(context.TODO(), bson.M{"_id": invite.ID}).Decode(&stored)
+    if stored.AcceptedAt != nil || stored.AcceptedBy != "" {
+        t.Errorf("invite still claimed after a failed join: %+v", stored)
+    }
+}
+
+func TestAcceptInvite(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme", models.Member{UserID: "owner", Role: models.RoleOwner})
+    _, token := insertInvite(t, org.ID, "me@example.com")
+
+    r := inviteeRouter("Me@Example.com", true)
+
+    if w := doJSON(r, http.MethodPost, "/invites/accept", map[string]interface{}{"token": token}); w.Code != http.StatusOK {
+        t.Fatalf("accept = %d: %s", w.Code, w.Body)
+    }
+    if w := doJSON(r, http.MethodPost, "/invites/accept", map[string]interface{}{"token": token}); w.Code != http.StatusConflict {
+        t.Errorf("second accept = %d, want 409: %s", w.Code, w.Body)
+    }
+
+    var stored models.Organization
+    database.GetCollection("organizations").FindOne(context.TODO(), bson.M{"_id": org.ID}).Decode(&stored)
+    if member, ok := stored.FindMember("test-user"); !ok || member.Role != models.RoleEditor {
+        t.Errorf("members = %+v, want test-user as editor", stored.Members)
+    }
+}
+
+func TestAcceptInviteRequiresTheInvitedVerifiedEmail(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme", models.Member{UserID: "owner", Role: models.RoleOwner})
+    invite, token := insertInvite(t, org.ID, "me@example.com")
+
+    tests := []struct {
+        name     string
+        email    string
+        verified bool
+    }{
+        {"no email claim", "", false},
+        {"unverified email", "me@example.com", false},
+        {"different email", "someone@example.com", true},
+    }
+    for _, tt := range tests {
+        w := doJSON(inviteeRouter(tt.email, tt.verified), http.MethodPost, "/invites/accept", map[string]interface{}{"token": token})
+        if w.Code != http.StatusForbidden {
+            t.Errorf("%s: status = %d, want 403: %s", tt.name, w.Code, w.Body)
+        }
+    }
+
+    var stored models.Invite
+    database.GetCollection("invites").FindOne(context.TODO(), bson.M{"_id": invite.ID}).Decode(&stored)
+    if stored.AcceptedAt != nil {
+        t.Errorf("invite claimed by a rejected caller: %+v", stored)
+    }
+}
+
+func TestCreateOrganizationIgnoresBodyMembers(t *testing.T) {
+    setupTestDB(t)
+    r := tenantRouter(primitive.NewObjectID(), "")
+    r.POST("/organizations", CreateOrganization)
+
+    w := doJSON(r, http.MethodPost, "/organizations", map[string]interface{}{
+        "name": "Acme",
+        "members": []map[string]interface{}{
+            {"user_id": "someone-else", "role": "owner", "email": "them@example.com"},
+        },
+    })
+    if w.Code != http.StatusCreated {
+        t.Fatalf("status = %d: %s", w.Code, w.Body)
+    }
+
+    var stored models.Organization
+    database.GetCollection("organizations").FindOne(context.TODO(), bson.M{"slug": "acme"}).Decode(&stored)
+    if len(stored.Members) != 1 || stored.Members[0].UserID != "test-user" || stored.Members[0].Role != models.RoleOwner {
+        t.Errorf("members = %+v, want only the creator as owner", stored.Members)
+    }
+}
\ No newline at end of file
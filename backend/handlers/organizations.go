@@ -14,6 +14,7 @@ import (
 
     "status-page-backend/database"
     "status-page-backend/models"
+    "status-page-backend/rollup"
 )
 
 func CreateOrganization(c *gin.Context) {
@@ -23,12 +24,58 @@ func CreateOrganization(c *gin.Context) {
         return
     }
 
+    if org.Slug == "" {
+        base := slugify(org.Name)
+        if !validSlug(base) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Could not derive a slug from the name, please provide one"})
+            return
+        }
+        slug, err := availableSlug(base)
+        if err != nil {
+            log.Printf("Error checking slug availability: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        org.Slug = slug
+    } else {
+        if problem := slugProblem(org.Slug); problem != "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+            return
+        }
+        taken, err := slugTaken(org.Slug, primitive.NilObjectID)
+        if err != nil {
+            log.Printf("Error checking slug availability: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if taken {
+            c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+            return
+        }
+    }
+    org.PreviousSlugs = nil
+
+    // The creator is the only member of the new organization, everyone else
+    // joins through an invite
+    org.Members = []models.Member{{
+        UserID: c.GetString("user_id"),
+        Role:   models.RoleOwner,
+        Email:  c.GetString("user_email"),
+    }}
+
+    org.ID = primitive.NilObjectID
+    org.Deleted = false
     org.CreatedAt = time.Now()
     org.UpdatedAt = time.Now()
 
     collection := database.GetCollection("organizations")
     result, err := collection.InsertOne(context.TODO(), org)
     if err != nil {
+        if mongo.IsDuplicateKeyError(err) {
+            c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+            return
+        }
+        log.Printf("Error creating organization: %v", err)
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
         return
     }
@@ -37,9 +84,22 @@ func CreateOrganization(c *gin.Context) {
     c.JSON(http.StatusCreated, gin.H{"organization": org})
 }
 
+// organizationResponse is an organization together with the caller's role in it
+type organizationResponse struct {
+    models.Organization
+    Role models.Role `json:"role"`
+}
+
 func GetOrganizations(c *gin.Context) {
+    userID := c.GetString("user_id")
+
     collection := database.GetCollection("organizations")
-    cursor, err := collection.Find(context.TODO(), bson.M{})
+    filter := bson.M{
+        "members.user_id": userID,
+        "deleted":         bson.M{"$ne": true},
+    }
+
+    cursor, err := collection.Find(context.TODO(), filter)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch organizations"})
         return
@@ -52,30 +112,158 @@ func GetOrganizations(c *gin.Context) {
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"organizations": organizations})
+    response := make([]organizationResponse, 0, len(organizations))
+    for _, org := range organizations {
+        member, _ := org.FindMember(userID)
+        response = append(response, organizationResponse{Organization: org, Role: member.Role})
+    }
+
+    c.JSON(http.StatusOK, gin.H{"organizations": response})
 }
 
+func GetOrganization(c *gin.Context) {
+    c.JSON(http.StatusOK, gin.H{"organization": organizationResponse{
+        Organization: currentOrganization(c),
+        Role:         currentRole(c),
+    }})
+}
 
 
-func GetPublicStatus(c *gin.Context) {
-    slug := c.Param("slug")
-    
-    // Find organization by slug (exclude deleted orgs)
-    orgCollection := database.GetCollection("organizations")
-    var org models.Organization
-    orgFilter := bson.M{
-        "slug":    slug,
-        "deleted": bson.M{"$ne": true}, // Exclude where deleted=true, include where deleted field doesn't exist
+
+func UpdateOrganization(c *gin.Context) {
+    var update struct {
+        Name        *string `json:"name"`
+        Slug        *string `json:"slug"`
+        Description *string `json:"description"`
     }
-    
-    err := orgCollection.FindOne(context.TODO(), orgFilter).Decode(&org)
-    if err != nil {
-        if err == mongo.ErrNoDocuments {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
-        } else {
-            log.Printf("Error finding organization: %v", err)
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    org := currentOrganization(c)
+    set := bson.M{"updated_at": time.Now()}
+
+    if update.Name != nil {
+        if *update.Name == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+            return
+        }
+        org.Name = *update.Name
+        set["name"] = org.Name
+    }
+    if update.Slug != nil && *update.Slug != org.Slug {
+        if problem := slugProblem(*update.Slug); problem != "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+            return
+        }
+        taken, err := slugTaken(*update.Slug, org.ID)
+        if err != nil {
+            log.Printf("Error checking slug availability: %v", err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if taken {
+            c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+            return
+        }
+
+        // Keep the old slug so existing links redirect to the new one
+        previous := []string{org.Slug}
+        for _, s := range org.PreviousSlugs {
+            if s != *update.Slug && s != org.Slug {
+                previous = append(previous, s)
+            }
         }
+        org.PreviousSlugs = previous
+        org.Slug = *update.Slug
+        set["slug"] = org.Slug
+        set["previous_slugs"] = org.PreviousSlugs
+    }
+    if update.Description != nil {
+        org.Description = *update.Description
+        set["description"] = org.Description
+    }
+
+    collection := database.GetCollection("organizations")
+    _, err := collection.UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":     org.ID,
+            "deleted": bson.M{"$ne": true},
+        },
+        bson.M{"$set": set},
+    )
+    if err != nil {
+        if mongo.IsDuplicateKeyError(err) {
+            c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+            return
+        }
+        log.Printf("Error updating organization: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization"})
+        return
+    }
+
+    org.UpdatedAt = set["updated_at"].(time.Time)
+    log.Printf("✅ Organization updated: %s", org.Name)
+    c.JSON(http.StatusOK, gin.H{"organization": org})
+}
+
+func DeleteOrganization(c *gin.Context) {
+    org := currentOrganization(c)
+
+    // Soft delete: set deleted = true, the slug stays reserved
+    collection := database.GetCollection("organizations")
+    _, err := collection.UpdateOne(
+        context.TODO(),
+        bson.M{"_id": org.ID},
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    )
+    if err != nil {
+        log.Printf("Error deleting organization: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete organization"})
+        return
+    }
+
+    // Its services and upcoming maintenance go with it, so the monitors,
+    // rollups and maintenance scheduler stop picking them up
+    for _, name := range []string{"services", "maintenance_series", "maintenances"} {
+        filter := bson.M{
+            "organization_id": org.ID,
+            "deleted":         bson.M{"$ne": true},
+        }
+        if name == "maintenances" {
+            filter["status"] = models.MaintenanceScheduled
+        }
+        _, err := database.GetCollection(name).UpdateMany(
+            context.TODO(),
+            filter,
+            bson.M{
+                "$set": bson.M{
+                    "deleted":    true,
+                    "updated_at": time.Now(),
+                },
+            },
+        )
+        if err != nil {
+            log.Printf("Error deleting %s of organization %s: %v", name, org.Name, err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete organization"})
+            return
+        }
+    }
+
+    log.Printf("✅ Organization deleted: %s", org.Name)
+    c.JSON(http.StatusOK, gin.H{"message": "Organization deleted successfully"})
+}
+
+func GetPublicStatus(c *gin.Context) {
+    org, ok := findPublicOrganization(c)
+    if !ok {
         return
     }
 
@@ -90,7 +278,7 @@ func GetPublicStatus(c *gin.Context) {
         "deleted":         bson.M{"$ne": true}, // Only show services where deleted is NOT true
     }
     
-    cursor, err := servicesCollection.Find(context.TODO(), servicesFilter)
+    cursor, err := servicesCollection.Find(context.TODO(), servicesFilter, serviceOrder())
     if err != nil {
         log.Printf("Error finding services: %v", err)
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
@@ -103,6 +291,9 @@ func GetPublicStatus(c *gin.Context) {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode services"})
         return
     }
+    for i := range services {
+        services[i] = services[i].Public()
+    }
 
     // Get non-deleted incidents for this organization
     incidentsCollection := database.GetCollection("incidents")
@@ -113,7 +304,7 @@ func GetPublicStatus(c *gin.Context) {
 
     // Sort by created_at descending, limit to 10 most recent
     findOptions := options.Find().
-        SetSort(bson.D{{"created_at", -1}}).
+        SetSort(bson.D{{Key: "created_at", Value: -1}}).
         SetLimit(10)
     
     incidentsCursor, err := incidentsCollection.Find(context.TODO(), incidentsFilter, findOptions)
@@ -129,10 +320,42 @@ func GetPublicStatus(c *gin.Context) {
             incidents = make([]models.Incident, 0)
         }
     }
+    publicIncidents(incidents)
+
+    // Uptime percentages per service, served from the daily rollups so the
+    // page doesn't recompute 90 days of history on every hit
+    serviceUptimes := make(map[string]map[string]float64, len(services))
+    dailyStatuses, err := findDailyStatuses(org.ID, rollup.MaxDays, time.Now())
+    if err != nil {
+        log.Printf("Error finding daily statuses: %v", err)
+    } else {
+        for _, service := range services {
+            serviceUptimes[service.ID.Hex()] = uptimeFromRollups(dailyStatuses[service.ID])
+        }
+    }
+
+    groups, err := findServiceGroups(org.ID)
+    if err != nil {
+        log.Printf("Error finding service groups: %v", err)
+        groups = make([]models.ServiceGroup, 0)
+    }
+    groupResponse, ungrouped := groupServices(groups, services)
+
+    activeMaintenances, upcomingMaintenances, err := findPublicMaintenances(org.ID)
+    if err != nil {
+        log.Printf("Error finding maintenances: %v", err)
+        activeMaintenances = make([]models.Maintenance, 0)
+        upcomingMaintenances = make([]models.Maintenance, 0)
+    }
 
     c.JSON(http.StatusOK, gin.H{
-        "organization": org,
-        "services":     services,
-        "incidents":    incidents,
+        "organization":          org.Public(),
+        "services":              services,
+        "groups":                groupResponse,
+        "ungrouped_services":    ungrouped,
+        "uptime":                serviceUptimes,
+        "incidents":             incidents,
+        "active_maintenances":   activeMaintenances,
+        "upcoming_maintenances": upcomingMaintenances,
     })
 }
\ No newline at end of file
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestPublicStatusHidesMonitors(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme")
+    service := insertService(t, org.ID, "API")
+    _, err := database.GetCollection("services").UpdateOne(context.TODO(),
+        bson.M{"_id": service.ID},
+        bson.M{"$set": bson.M{
+            "monitor":       models.MonitorConfig{Enabled: true, Type: models.MonitorTCP, Target: "10.0.0.5:5432"},
+            "monitor_state": models.MonitorState{LastMessage: "dial tcp 10.0.0.5:5432: connection refused"},
+        }},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/status/:slug", GetPublicStatus)
+    r.GET("/status/:slug/history", GetPublicStatusHistory)
+
+    for _, path := range []string{"/status/acme", "/status/acme/history"} {
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+        if w.Code != http.StatusOK {
+            t.Fatalf("%s = %d: %s", path, w.Code, w.Body)
+        }
+        body := w.Body.String()
+        if !strings.Contains(body, service.ID.Hex()) {
+            t.Errorf("%s doesn't list the service", path)
+        }
+        for _, leak := range []string{"monitor", "10.0.0.5"} {
+            if strings.Contains(body, leak) {
+                t.Errorf("%s exposes %q: %s", path, leak, body)
+            }
+        }
+    }
+}
+
+func TestDeleteOrganizationRetiresItsServices(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme")
+    service := insertService(t, org.ID, "API")
+    window := insertMaintenance(t, org.ID, "Database upgrade", service.ID)
+    other := insertService(t, primitive.NewObjectID(), "Other API")
+
+    r := tenantRouter(org.ID, models.RoleOwner)
+    r.Use(func(c *gin.Context) {
+        c.Set("organization", org)
+        c.Next()
+    })
+    r.DELETE("/organizations/:id", DeleteOrganization)
+    if w := doJSON(r, http.MethodDelete, "/organizations/"+org.ID.Hex(), nil); w.Code != http.StatusOK {
+        t.Fatalf("delete = %d: %s", w.Code, w.Body)
+    }
+
+    if !findService(t, service.ID).Deleted {
+        t.Error("service of the deleted organization is still live")
+    }
+    if findService(t, other.ID).Deleted {
+        t.Error("service of another organization was deleted")
+    }
+    var stored models.Maintenance
+    database.GetCollection("maintenances").FindOne(context.TODO(), bson.M{"_id": window.ID}).Decode(&stored)
+    if !stored.Deleted {
+        t.Error("scheduled maintenance of the deleted organization is still live")
+    }
+}
+
+func TestPublicStatusHidesMembers(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme", models.Member{UserID: "user-owner", Role: models.RoleOwner, Email: "owner@acme.test"})
+    _, err := database.GetCollection("organizations").UpdateOne(context.TODO(),
+        bson.M{"_id": org.ID},
+        bson.M{"$set": bson.M{"previous_slugs": []string{"acme-old"}, "description": "Acme services"}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/status/:slug", GetPublicStatus)
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status/acme", nil))
+    if w.Code != http.StatusOK {
+        t.Fatalf("status = %d: %s", w.Code, w.Body)
+    }
+
+    body := w.Body.String()
+    if !strings.Contains(body, "Acme services") || !strings.Contains(body, org.ID.Hex()) {
+        t.Errorf("public organization is missing its id or description: %s", body)
+    }
+    for _, leak := range []string{"members", "user-owner", "owner@acme.test", "previous_slugs", "acme-old"} {
+        if strings.Contains(body, leak) {
+            t.Errorf("public status exposes %q: %s", leak, body)
+        }
+    }
+}
\ No newline at end of file
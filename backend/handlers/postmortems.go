@@ -0,0 +1,188 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+func GetPostmortem(c *gin.Context) {
+    incidentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    incident, ok := findTenantIncident(c, orgID, incidentID)
+    if !ok {
+        return
+    }
+    if incident.Postmortem == nil {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Postmortem not found"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"postmortem": incident.Postmortem})
+}
+
+// UpdatePostmortem creates or edits an incident's postmortem, changing only
+// the fields present in the request. Publishing requires the incident to be
+// resolved.
+func UpdatePostmortem(c *gin.Context) {
+    incidentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Body        *string                  `json:"body"`
+        RootCause   *string                  `json:"root_cause"`
+        ActionItems *[]models.ActionItem     `json:"action_items"`
+        Status      *models.PostmortemStatus `json:"status"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    incident, ok := findTenantIncident(c, orgID, incidentID)
+    if !ok {
+        return
+    }
+
+    now := time.Now()
+    userID := c.GetString("user_id")
+    postmortem := models.Postmortem{
+        ActionItems: make([]models.ActionItem, 0),
+        Status:      models.PostmortemDraft,
+        CreatedBy:   userID,
+        CreatedAt:   now,
+    }
+    if incident.Postmortem != nil {
+        postmortem = *incident.Postmortem
+    }
+    wasPublished := postmortem.Status == models.PostmortemPublished
+
+    if update.Body != nil {
+        postmortem.Body = *update.Body
+    }
+    if update.RootCause != nil {
+        postmortem.RootCause = *update.RootCause
+    }
+    if update.ActionItems != nil {
+        items := *update.ActionItems
+        for i := range items {
+            if items[i].Description == "" {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Action items need a description"})
+                return
+            }
+            if items[i].ID.IsZero() {
+                items[i].ID = primitive.NewObjectID()
+            }
+        }
+        if items == nil {
+            items = make([]models.ActionItem, 0)
+        }
+        postmortem.ActionItems = items
+    }
+    if update.Status != nil {
+        switch *update.Status {
+        case models.PostmortemDraft:
+            postmortem.PublishedAt = nil
+        case models.PostmortemPublished:
+            if incident.Status != models.IncidentStatusResolved {
+                c.JSON(http.StatusConflict, gin.H{"error": "Only postmortems of resolved incidents can be published"})
+                return
+            }
+            if !wasPublished {
+                postmortem.PublishedAt = &now
+            }
+        default:
+            c.JSON(http.StatusBadRequest, gin.H{"error": "status must be draft or published"})
+            return
+        }
+        postmortem.Status = *update.Status
+    }
+
+    postmortem.UpdatedBy = userID
+    postmortem.UpdatedAt = now
+
+    _, err = database.GetCollection("incidents").UpdateOne(
+        context.TODO(),
+        bson.M{
+            "_id":             incidentID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{"$set": bson.M{"postmortem": postmortem}},
+    )
+    if err != nil {
+        log.Printf("Error saving postmortem: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save postmortem"})
+        return
+    }
+
+    // Drafts stay private, so only published postmortems are broadcast
+    if postmortem.Status == models.PostmortemPublished {
+        websocketMessage := websocket.Message{
+            Type: "postmortem_published",
+            Data: map[string]interface{}{
+                "incident_id":     incident.ID.Hex(),
+                "incident_title":  incident.Title,
+                "organization_id": orgID.Hex(),
+                "published_at":    postmortem.PublishedAt.Unix(),
+                "action":          "postmortem_published",
+                "timestamp":       now.Unix(),
+            },
+        }
+        BroadcastWebSocket(c, websocketMessage)
+    }
+
+    log.Printf("✅ Postmortem saved for incident: %s (%s)", incident.Title, postmortem.Status)
+    c.JSON(http.StatusOK, gin.H{"postmortem": postmortem})
+}
+
+// findTenantIncident loads an incident of the organization, writing the
+// error response if there is none
+func findTenantIncident(c *gin.Context, orgID, incidentID primitive.ObjectID) (models.Incident, bool) {
+    var incident models.Incident
+    err := database.GetCollection("incidents").FindOne(context.TODO(), bson.M{
+        "_id":             incidentID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&incident)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+        } else {
+            log.Printf("Error finding incident: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return incident, false
+    }
+    return incident, true
+}
\ No newline at end of file
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+    "context"
+    "encoding/base64"
+    "errors"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// publicIncidents prepares incidents for the public status page
+func publicIncidents(incidents []models.Incident) {
+    for i := range incidents {
+        incidents[i] = incidents[i].Public()
+    }
+}
+
+// incidentCursor encodes the position after an incident in newest-first
+// order. Mongo stores times in milliseconds, so that is all it keeps.
+func incidentCursor(incident models.Incident) string {
+    raw := strconv.FormatInt(incident.CreatedAt.UnixMilli(), 10) + "." + incident.ID.Hex()
+    return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseIncidentCursor returns a filter for the incidents after the cursor
+func parseIncidentCursor(cursor string) (bson.M, error) {
+    invalid := errors.New("invalid cursor")
+
+    raw, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return nil, invalid
+    }
+    millis, hexID, ok := strings.Cut(string(raw), ".")
+    if !ok {
+        return nil, invalid
+    }
+    ms, err := strconv.ParseInt(millis, 10, 64)
+    if err != nil {
+        return nil, invalid
+    }
+    id, err := primitive.ObjectIDFromHex(hexID)
+    if err != nil {
+        return nil, invalid
+    }
+
+    createdAt := time.UnixMilli(ms)
+    return bson.M{"$or": bson.A{
+        bson.M{"created_at": bson.M{"$lt": createdAt}},
+        bson.M{"created_at": createdAt, "_id": bson.M{"$lt": id}},
+    }}, nil
+}
+
+// GetPublicIncidents pages through a public status page's incidents, newest
+// first. Pass next_cursor back as ?cursor= for the next page. Filters:
+// status (comma separated), service, and from/to on when the incident began.
+func GetPublicIncidents(c *gin.Context) {
+    org, ok := findPublicOrganization(c)
+    if !ok {
+        return
+    }
+
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+    if err != nil || limit < 1 || limit > 100 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 100"})
+        return
+    }
+
+    filter := bson.M{
+        "organization_id": org.ID,
+        "deleted":         bson.M{"$ne": true},
+    }
+
+    if status := c.Query("status"); status != "" {
+        statuses := make([]models.IncidentStatus, 0)
+        for _, s := range strings.Split(status, ",") {
+            incidentStatus := models.IncidentStatus(strings.TrimSpace(s))
+            if !incidentStatus.Valid() {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident status: " + s})
+                return
+            }
+            statuses = append(statuses, incidentStatus)
+        }
+        filter["status"] = bson.M{"$in": statuses}
+    }
+
+    if service := c.Query("service"); service != "" {
+        serviceID, err := primitive.ObjectIDFromHex(service)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+            return
+        }
+        filter["affected_services"] = serviceID
+    }
+
+    createdAt := bson.M{}
+    if from := c.Query("from"); from != "" {
+        t, err := time.Parse(time.RFC3339, from)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 time"})
+            return
+        }
+        createdAt["$gte"] = t
+    }
+    if to := c.Query("to"); to != "" {
+        t, err := time.Parse(time.RFC3339, to)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 time"})
+            return
+        }
+        createdAt["$lt"] = t
+    }
+    if len(createdAt) > 0 {
+        filter["created_at"] = createdAt
+    }
+
+    if cursor := c.Query("cursor"); cursor != "" {
+        after, err := parseIncidentCursor(cursor)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+            return
+        }
+        filter = bson.M{"$and": bson.A{filter, after}}
+    }
+
+    // One extra tells us whether there is another page
+    findOptions := options.Find().
+        SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+        SetLimit(int64(limit + 1))
+
+    cursor, err := database.GetCollection("incidents").Find(context.TODO(), filter, findOptions)
+    if err != nil {
+        log.Printf("Error finding incidents: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch incidents"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    incidents := make([]models.Incident, 0)
+    if err := cursor.All(context.TODO(), &incidents); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode incidents"})
+        return
+    }
+
+    nextCursor := ""
+    if len(incidents) > limit {
+        incidents = incidents[:limit]
+        nextCursor = incidentCursor(incidents[limit-1])
+    }
+    publicIncidents(incidents)
+
+    c.JSON(http.StatusOK, gin.H{
+        "incidents":   incidents,
+        "limit":       limit,
+        "next_cursor": nextCursor,
+    })
+}
+
+// GetPublicIncident returns one incident of a public status page with its
+// full timeline and, once published, its postmortem
+func GetPublicIncident(c *gin.Context) {
+    org, ok := findPublicOrganization(c)
+    if !ok {
+        return
+    }
+
+    incidentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+        return
+    }
+
+    var incident models.Incident
+    err = database.GetCollection("incidents").FindOne(context.TODO(), bson.M{
+        "_id":             incidentID,
+        "organization_id": org.ID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&incident)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+        } else {
+            log.Printf("Error finding incident: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"incident": incident.Public()})
+}
\ No newline at end of file
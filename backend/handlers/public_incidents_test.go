@@ -0,0 +1,143 @@
+package handlers
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestIncidentCursorRoundTrip(t *testing.T) {
+    createdAt := time.Date(2024, 5, 1, 12, 30, 15, 123456789, time.UTC)
+    incident := models.Incident{ID: primitive.NewObjectID(), CreatedAt: createdAt}
+
+    filter, err := parseIncidentCursor(incidentCursor(incident))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Only milliseconds survive, matching what Mongo stored
+    wantTime := createdAt.Truncate(time.Millisecond)
+    or, _ := filter["$or"].(bson.A)
+    if len(or) != 2 {
+        t.Fatalf("filter = %v, want two branches", filter)
+    }
+    before := or[0].(bson.M)["created_at"].(bson.M)["$lt"].(time.Time)
+    if !before.Equal(wantTime) {
+        t.Errorf("earlier branch created_at < %v, want %v", before, wantTime)
+    }
+
+    // Equal created_at falls back to the ID
+    tie := or[1].(bson.M)
+    if at := tie["created_at"].(time.Time); !at.Equal(wantTime) {
+        t.Errorf("tie branch created_at = %v, want %v", at, wantTime)
+    }
+    if id := tie["_id"].(bson.M)["$lt"]; id != incident.ID {
+        t.Errorf("tie branch _id < %v, want %v", id, incident.ID)
+    }
+}
+
+func TestParseIncidentCursorRejectsGarbage(t *testing.T) {
+    encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+    id := primitive.NewObjectID().Hex()
+
+    for _, cursor := range []string{
+        "",
+        "not base64!",
+        base64.StdEncoding.EncodeToString([]byte("1714566615123." + id)),
+        encode("1714566615123"),
+        encode("1714566615123-" + id),
+        encode("soon." + id),
+        encode("1714566615123.not-an-id"),
+        encode("1714566615123."),
+        encode("." + id),
+    } {
+        if filter, err := parseIncidentCursor(cursor); err == nil {
+            t.Errorf("parseIncidentCursor(%q) = %v, want an error", cursor, filter)
+        }
+    }
+}
+
+// Paging visits every incident once, including ones created in the same
+// millisecond, and stops with an empty cursor
+func TestPublicIncidentsPaging(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme")
+
+    base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    offsets := []time.Duration{0, time.Hour, time.Hour, time.Hour, 2 * time.Hour}
+    for i, offset := range offsets {
+        incident := insertIncident(t, org.ID, "Incident "+string(rune('A'+i)))
+        _, err := database.GetCollection("incidents").UpdateOne(context.TODO(),
+            bson.M{"_id": incident.ID},
+            bson.M{"$set": bson.M{"created_at": base.Add(offset)}},
+        )
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+    insertIncident(t, primitive.NewObjectID(), "Someone else's")
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/status/:slug/incidents", GetPublicIncidents)
+
+    seen := make(map[string]bool)
+    var order []models.Incident
+    path := "/status/acme/incidents?limit=2"
+    for pages := 0; ; pages++ {
+        if pages > len(offsets) {
+            t.Fatal("paging never ended")
+        }
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+        if w.Code != http.StatusOK {
+            t.Fatalf("status = %d: %s", w.Code, w.Body)
+        }
+        var page struct {
+            Incidents  []models.Incident `json:"incidents"`
+            NextCursor string            `json:"next_cursor"`
+        }
+        if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+            t.Fatal(err)
+        }
+        for _, incident := range page.Incidents {
+            if seen[incident.ID.Hex()] {
+                t.Errorf("%s returned twice", incident.Title)
+            }
+            seen[incident.ID.Hex()] = true
+            order = append(order, incident)
+        }
+        if page.NextCursor == "" {
+            break
+        }
+        path = "/status/acme/incidents?limit=2&cursor=" + page.NextCursor
+    }
+
+    if len(order) != len(offsets) {
+        t.Fatalf("paged through %d incidents, want %d", len(order), len(offsets))
+    }
+    for i := 1; i < len(order); i++ {
+        prev, cur := order[i-1], order[i]
+        if cur.CreatedAt.After(prev.CreatedAt) ||
+            (cur.CreatedAt.Equal(prev.CreatedAt) && cur.ID.Hex() > prev.ID.Hex()) {
+            t.Errorf("%s listed after %s", cur.Title, prev.Title)
+        }
+    }
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status/acme/incidents?cursor=bogus", nil))
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("invalid cursor status = %d, want 400", w.Code)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+// serviceGroupResponse is a group with its services and their aggregated status
+type serviceGroupResponse struct {
+    models.ServiceGroup
+    Status   models.ServiceStatus `json:"status"`
+    Services []models.Service     `json:"services"`
+}
+
+// groupServices nests services under their groups, keeping the order of both
+// slices. Services without a group are returned separately.
+func groupServices(groups []models.ServiceGroup, services []models.Service) ([]serviceGroupResponse, []models.Service) {
+    response := make([]serviceGroupResponse, len(groups))
+    index := make(map[primitive.ObjectID]int, len(groups))
+    for i, group := range groups {
+        response[i] = serviceGroupResponse{ServiceGroup: group, Services: make([]models.Service, 0)}
+        index[group.ID] = i
+    }
+
+    ungrouped := make([]models.Service, 0)
+    for _, service := range services {
+        if service.GroupID != nil {
+            if i, ok := index[*service.GroupID]; ok {
+                response[i].Services = append(response[i].Services, service)
+                continue
+            }
+        }
+        ungrouped = append(ungrouped, service)
+    }
+
+    for i := range response {
+        statuses := make([]models.ServiceStatus, len(response[i].Services))
+        for j, service := range response[i].Services {
+            statuses[j] = service.Status
+        }
+        response[i].Status = models.WorstStatus(statuses...)
+    }
+
+    return response, ungrouped
+}
+
+// findServiceGroups returns an organization's groups in display order
+func findServiceGroups(orgID primitive.ObjectID) ([]models.ServiceGroup, error) {
+    collection := database.GetCollection("service_groups")
+    cursor, err := collection.Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        options.Find().SetSort(bson.D{
+            {Key: "position", Value: 1},
+            {Key: "created_at", Value: 1},
+        }),
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    groups := make([]models.ServiceGroup, 0)
+    if err := cursor.All(context.TODO(), &groups); err != nil {
+        return nil, err
+    }
+    return groups, nil
+}
+
+// serviceGroupExists reports whether groupID is a live group of the organization
+func serviceGroupExists(orgID, groupID primitive.ObjectID) (bool, error) {
+    collection := database.GetCollection("service_groups")
+    count, err := collection.CountDocuments(context.TODO(), bson.M{
+        "_id":             groupID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    })
+    return count > 0, err
+}
+
+func GetServiceGroups(c *gin.Context) {
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    groups, err := findServiceGroups(orgID)
+    if err != nil {
+        log.Printf("Error finding service groups: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service groups"})
+        return
+    }
+
+    collection := database.GetCollection("services")
+    cursor, err := collection.Find(
+        context.TODO(),
+        bson.M{
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        serviceOrder(),
+    )
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var services []models.Service
+    if err := cursor.All(context.TODO(), &services); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode services"})
+        return
+    }
+
+    response, ungrouped := groupServices(groups, services)
+    c.JSON(http.StatusOK, gin.H{"groups": response, "ungrouped_services": ungrouped})
+}
+
+func CreateServiceGroup(c *gin.Context) {
+    var group models.ServiceGroup
+    if err := c.ShouldBindJSON(&group); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    if group.Name == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+        return
+    }
+
+    orgID := c.GetString("organization_id")
+    group.ID = primitive.NilObjectID
+    group.OrganizationID, _ = primitive.ObjectIDFromHex(orgID)
+    group.Deleted = false
+    group.CreatedAt = time.Now()
+    group.UpdatedAt = time.Now()
+
+    collection := database.GetCollection("service_groups")
+
+    // New groups go to the end of the list
+    var last models.ServiceGroup
+    err := collection.FindOne(
+        context.TODO(),
+        bson.M{"organization_id": group.OrganizationID, "deleted": bson.M{"$ne": true}},
+        options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}}),
+    ).Decode(&last)
+    if err != nil && err != mongo.ErrNoDocuments {
+        log.Printf("Error finding last service group position: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if err == nil {
+        group.Position = last.Position + 1
+    } else {
+        group.Position = 0
+    }
+
+    result, err := collection.InsertOne(context.TODO(), group)
+    if err != nil {
+        log.Printf("Error creating service group: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service group"})
+        return
+    }
+
+    group.ID = result.InsertedID.(primitive.ObjectID)
+
+    // Broadcast group creation via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "service_group_created",
+        Data: map[string]interface{}{
+            "group_id":        group.ID.Hex(),
+            "group_name":      group.Name,
+            "group_desc":      group.Description,
+            "group_position":  group.Position,
+            "organization_id": group.OrganizationID.Hex(),
+            "action":          "service_group_created",
+            "timestamp":       time.Now().Unix(),
+        },
+    }
+    BroadcastWebSocket(c, websocketMessage)
+
+    log.Printf("✅ Service group created: %s", group.Name)
+    c.JSON(http.StatusCreated, gin.H{"group": group})
+}
+
+func UpdateServiceGroup(c *gin.Context) {
+    groupID := c.Param("id")
+    objID, err := primitive.ObjectIDFromHex(groupID)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service group ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Name        *string `json:"name"`
+        Description *string `json:"description"`
+        Position    *int    `json:"position"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    set := bson.M{"updated_at": time.Now()}
+    if update.Name != nil {
+        if *update.Name == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+            return
+        }
+        set["name"] = *update.Name
+    }
+    if update.Description != nil {
+        set["description"] = *update.Description
+    }
+    if update.Position != nil {
+        set["position"] = *update.Position
+    }
+
+    collection := database.GetCollection("service_groups")
+    var group models.ServiceGroup
+    err = collection.FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{"$set": set},
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&group)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Service group not found"})
+        } else {
+            log.Printf("Error updating service group: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service group"})
+        }
+        return
+    }
+
+    // Broadcast group update via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "service_group_updated",
+        Data: map[string]interface{}{
+            "group_id":        group.ID.Hex(),
+            "group_name":      group.Name,
+            "group_desc":      group.Description,
+            "group_position":  group.Position,
+            "organization_id": group.OrganizationID.Hex(),
+            "action":          "service_group_updated",
+            "timestamp":       time.Now().Unix(),
+        },
+    }
+    BroadcastWebSocket(c, websocketMessage)
+
+    log.Printf("✅ Service group updated: %s", group.Name)
+    c.JSON(http.StatusOK, gin.H{"group": group})
+}
+
+func DeleteServiceGroup(c *gin.Context) {
+    groupID := c.Param("id")
+    objID, err := primitive.ObjectIDFromHex(groupID)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service group ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    // Soft delete: set deleted = true
+    collection := database.GetCollection("service_groups")
+    var group models.ServiceGroup
+    err = collection.FindOneAndUpdate(
+        context.TODO(),
+        bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        },
+        bson.M{
+            "$set": bson.M{
+                "deleted":    true,
+                "updated_at": time.Now(),
+            },
+        },
+    ).Decode(&group)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Service group not found"})
+        } else {
+            log.Printf("Error deleting service group: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service group"})
+        }
+        return
+    }
+
+    // Services of a deleted group become ungrouped
+    _, err = database.GetCollection("services").UpdateMany(
+        context.TODO(),
+        bson.M{"organization_id": orgID, "group_id": objID},
+        bson.M{
+            "$unset": bson.M{"group_id": ""},
+            "$set":   bson.M{"updated_at": time.Now()},
+        },
+    )
+    if err != nil {
+        log.Printf("Error ungrouping services: %v", err)
+    }
+
+    // Broadcast group deletion via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "service_group_deleted",
+        Data: map[string]interface{}{
+            "group_id":        groupID,
+            "group_name":      group.Name,
+            "organization_id": orgID.Hex(),
+            "action":          "service_group_deleted",
+            "timestamp":       time.Now().Unix(),
+        },
+    }
+    BroadcastWebSocket(c, websocketMessage)
+
+    log.Printf("✅ Service group deleted: %s", group.Name)
+    c.JSON(http.StatusOK, gin.H{"message": "Service group deleted successfully"})
+}
\ No newline at end of file
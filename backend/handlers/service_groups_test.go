@@ -0,0 +1,76 @@
+package handlers
+
+import (
+    "reflect"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/models"
+)
+
+func TestGroupServices(t *testing.T) {
+    api := models.ServiceGroup{ID: primitive.NewObjectID(), Name: "API"}
+    web := models.ServiceGroup{ID: primitive.NewObjectID(), Name: "Web"}
+    empty := models.ServiceGroup{ID: primitive.NewObjectID(), Name: "Empty"}
+    deletedGroup := primitive.NewObjectID()
+
+    service := func(name string, status models.ServiceStatus, group *primitive.ObjectID) models.Service {
+        return models.Service{ID: primitive.NewObjectID(), Name: name, Status: status, GroupID: group}
+    }
+    services := []models.Service{
+        service("rest", models.StatusOperational, &api.ID),
+        service("cdn", models.StatusMaintenance, &web.ID),
+        service("graphql", models.StatusPartialOutage, &api.ID),
+        service("billing", models.StatusMajorOutage, nil),
+        service("frontend", models.StatusDegradedPerf, &web.ID),
+        service("legacy", models.StatusOperational, &deletedGroup),
+        service("grpc", models.StatusDegradedPerf, &api.ID),
+    }
+
+    groups, ungrouped := groupServices([]models.ServiceGroup{api, web, empty}, services)
+
+    want := []struct {
+        name     string
+        status   models.ServiceStatus
+        services []string
+    }{
+        {"API", models.StatusPartialOutage, []string{"rest", "graphql", "grpc"}},
+        {"Web", models.StatusDegradedPerf, []string{"cdn", "frontend"}},
+        {"Empty", models.StatusOperational, nil},
+    }
+    if len(groups) != len(want) {
+        t.Fatalf("got %d groups, want %d", len(groups), len(want))
+    }
+    for i, w := range want {
+        g := groups[i]
+        if g.Name != w.name || g.Status != w.status {
+            t.Errorf("group %d = %s/%s, want %s/%s", i, g.Name, g.Status, w.name, w.status)
+        }
+        if g.Services == nil {
+            t.Errorf("group %s has nil services, want an empty list", g.Name)
+        }
+        if got := serviceNames(g.Services); !reflect.DeepEqual(got, w.services) {
+            t.Errorf("group %s services = %v, want %v", g.Name, got, w.services)
+        }
+    }
+
+    // Services without a group, or whose group no longer exists, stay visible
+    if got := serviceNames(ungrouped); !reflect.DeepEqual(got, []string{"billing", "legacy"}) {
+        t.Errorf("ungrouped = %v, want [billing legacy]", got)
+    }
+
+    groups, ungrouped = groupServices(nil, nil)
+    if len(groups) != 0 || ungrouped == nil || len(ungrouped) != 0 {
+        t.Errorf("groupServices(nil, nil) = %v, %v", groups, ungrouped)
+    }
+}
+
+// serviceNames lists the names of services in order
+func serviceNames(services []models.Service) []string {
+    var names []string
+    for _, service := range services {
+        names = append(names, service.Name)
+    }
+    return names
+}
\ No newline at end of file
@@ -10,6 +10,7 @@ import (
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/bson/primitive"
     "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
 
     "status-page-backend/database"
     "status-page-backend/models"
@@ -30,7 +31,7 @@ func GetServices(c *gin.Context) {
         "deleted":         bson.M{"$ne": true},
     }
     
-    cursor, err := collection.Find(context.TODO(), filter)
+    cursor, err := collection.Find(context.TODO(), filter, serviceOrder())
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
         return
@@ -56,10 +57,56 @@ func CreateService(c *gin.Context) {
     orgID := c.GetString("organization_id")
     service.OrganizationID, _ = primitive.ObjectIDFromHex(orgID)
     service.Status = models.StatusOperational
+    service.Deleted = false
     service.CreatedAt = time.Now()
-    service.UpdatedAt = time.Now()
+    service.UpdatedAt = service.CreatedAt
+
+    service.MonitorState = nil
+    service.LastHeartbeatAt = nil
+    service.HeartbeatExpectedSince = nil
+    if service.Monitor != nil {
+        if err := validateMonitor(service.Monitor, service.URL); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        if isHeartbeatMonitor(service.Monitor) {
+            service.HeartbeatExpectedSince = &service.CreatedAt
+        }
+    }
+
+    if service.GroupID != nil {
+        exists, err := serviceGroupExists(service.OrganizationID, *service.GroupID)
+        if err != nil {
+            log.Printf("Error finding service group: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+        if !exists {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Service group not found"})
+            return
+        }
+    }
 
     collection := database.GetCollection("services")
+
+    // New services go to the end of the list
+    var last models.Service
+    err := collection.FindOne(
+        context.TODO(),
+        bson.M{"organization_id": service.OrganizationID, "deleted": bson.M{"$ne": true}},
+        options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}}),
+    ).Decode(&last)
+    if err != nil && err != mongo.ErrNoDocuments {
+        log.Printf("Error finding last service position: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if err == nil {
+        service.Position = last.Position + 1
+    } else {
+        service.Position = 0
+    }
+
     result, err := collection.InsertOne(context.TODO(), service)
     if err != nil {
         log.Printf("Error creating service: %v", err)
@@ -69,6 +116,16 @@ func CreateService(c *gin.Context) {
 
     service.ID = result.InsertedID.(primitive.ObjectID)
 
+    // The initial status starts the service's history, uptime is measured from here
+    recordStatusChange(models.StatusChange{
+        OrganizationID: service.OrganizationID,
+        ServiceID:      service.ID,
+        NewStatus:      service.Status,
+        Message:        "Service created",
+        Actor:          c.GetString("user_id"),
+        CreatedAt:      service.CreatedAt,
+    })
+
     // Broadcast service creation via WebSocket
     websocketMessage := websocket.Message{
         Type: "service_created",
@@ -89,7 +146,32 @@ func CreateService(c *gin.Context) {
     c.JSON(http.StatusCreated, gin.H{"service": service})
 }
 
-func UpdateServiceStatus(c *gin.Context) {
+// isHeartbeatMonitor reports whether cfg is an enabled heartbeat monitor
+func isHeartbeatMonitor(cfg *models.MonitorConfig) bool {
+    return cfg != nil && cfg.Enabled && cfg.Type == models.MonitorHeartbeat
+}
+
+// validateMonitor applies defaults to a monitor config and checks that it can
+// run against the service
+func validateMonitor(cfg *models.MonitorConfig, serviceURL string) error {
+    cfg.ApplyDefaults()
+    if err := cfg.Validate(); err != nil {
+        return err
+    }
+
+    _, err := cfg.TargetFor(serviceURL)
+    return err
+}
+
+// serviceOrder sorts services by their display position
+func serviceOrder() *options.FindOptions {
+    return options.Find().SetSort(bson.D{
+        {Key: "position", Value: 1},
+        {Key: "created_at", Value: 1},
+    })
+}
+
+func UpdateService(c *gin.Context) {
     serviceID := c.Param("id")
     objID, err := primitive.ObjectIDFromHex(serviceID)
     if err != nil {
@@ -97,9 +179,19 @@ func UpdateServiceStatus(c *gin.Context) {
         return
     }
 
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
     var update struct {
-        Status  models.ServiceStatus `json:"status"`
-        Message string               `json:"message"`
+        Name        *string `json:"name"`
+        Description *string `json:"description"`
+        URL         *string `json:"url"`
+        Position    *int    `json:"position"`
+        GroupID     *string `json:"group_id"` // empty string removes the service from its group
+        Monitor     *models.MonitorConfig `json:"monitor"`
     }
 
     if err := c.ShouldBindJSON(&update); err != nil {
@@ -107,61 +199,252 @@ func UpdateServiceStatus(c *gin.Context) {
         return
     }
 
-    // Get existing service for broadcasting
-    collection := database.GetCollection("services")
-    var existingService models.Service
-    err = collection.FindOne(context.TODO(), bson.M{
-        "_id":     objID,
-        "deleted": bson.M{"$ne": true},
-    }).Decode(&existingService)
-    
-    if err != nil {
-        if err == mongo.ErrNoDocuments {
-            c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+    set := bson.M{"updated_at": time.Now()}
+    updateDoc := bson.M{"$set": set}
+    if update.Name != nil {
+        if *update.Name == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+            return
+        }
+        set["name"] = *update.Name
+    }
+    if update.Description != nil {
+        set["description"] = *update.Description
+    }
+    if update.URL != nil {
+        set["url"] = *update.URL
+    }
+    if update.Position != nil {
+        set["position"] = *update.Position
+    }
+    if update.GroupID != nil {
+        if *update.GroupID == "" {
+            updateDoc["$unset"] = bson.M{"group_id": ""}
         } else {
+            groupID, err := primitive.ObjectIDFromHex(*update.GroupID)
+            if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service group ID"})
+                return
+            }
+            exists, err := serviceGroupExists(orgID, groupID)
+            if err != nil {
+                log.Printf("Error finding service group: %v", err)
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+                return
+            }
+            if !exists {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Service group not found"})
+                return
+            }
+            set["group_id"] = groupID
+        }
+    }
+
+    collection := database.GetCollection("services")
+
+    // The monitor is checked against the URL it probes, so changing either
+    // one revalidates the pair
+    if update.Monitor != nil || update.URL != nil {
+        var existing models.Service
+        err := collection.FindOne(context.TODO(), bson.M{
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
+        }).Decode(&existing)
+        if err != nil && err != mongo.ErrNoDocuments {
             log.Printf("Error finding service: %v", err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            return
+        }
+
+        serviceURL := existing.URL
+        if update.URL != nil {
+            serviceURL = *update.URL
+        }
+        if update.Monitor != nil {
+            if err := validateMonitor(update.Monitor, serviceURL); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+            }
+            set["monitor"] = update.Monitor
+            // Turning on a heartbeat monitor starts its deadline
+            if isHeartbeatMonitor(update.Monitor) && !isHeartbeatMonitor(existing.Monitor) {
+                set["heartbeat_expected_since"] = time.Now()
+            }
+        } else if existing.Monitor != nil && existing.Monitor.Enabled {
+            monitor := *existing.Monitor
+            if err := validateMonitor(&monitor, serviceURL); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "URL doesn't work with the service's monitor: " + err.Error()})
+                return
+            }
         }
-        return
     }
 
-    // Update service status
-    _, err = collection.UpdateOne(
+    var service models.Service
+    err = collection.FindOneAndUpdate(
         context.TODO(),
         bson.M{
-            "_id":     objID,
-            "deleted": bson.M{"$ne": true},
+            "_id":             objID,
+            "organization_id": orgID,
+            "deleted":         bson.M{"$ne": true},
         },
-        bson.M{
-            "$set": bson.M{
-                "status":     update.Status,
-                "updated_at": time.Now(),
-            },
+        updateDoc,
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&service)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        } else {
+            log.Printf("Error updating service: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+        }
+        return
+    }
+
+    // Broadcast service update via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "service_updated",
+        Data: map[string]interface{}{
+            "service_id":       service.ID.Hex(),
+            "service_name":     service.Name,
+            "service_status":   string(service.Status),
+            "service_desc":     service.Description,
+            "service_url":      service.URL,
+            "service_position": service.Position,
+            "group_id":         service.GroupID,
+            "organization_id":  service.OrganizationID.Hex(),
+            "action":           "service_updated",
+            "timestamp":        time.Now().Unix(),
         },
-    )
+    }
+    BroadcastWebSocket(c, websocketMessage)
+
+    log.Printf("✅ Service updated: %s", service.Name)
+    c.JSON(http.StatusOK, gin.H{"service": service})
+}
+
+func ReorderServices(c *gin.Context) {
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
     if err != nil {
-        log.Printf("Error updating service status: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var req struct {
+        ServiceIDs []primitive.ObjectID `json:"service_ids" binding:"required"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
     }
 
-    // Broadcast status update via WebSocket
+    seen := make(map[primitive.ObjectID]bool, len(req.ServiceIDs))
+    writes := make([]mongo.WriteModel, 0, len(req.ServiceIDs))
+    now := time.Now()
+    for i, id := range req.ServiceIDs {
+        if seen[id] {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicate service ID: " + id.Hex()})
+            return
+        }
+        seen[id] = true
+
+        writes = append(writes, mongo.NewUpdateOneModel().
+            SetFilter(bson.M{
+                "_id":             id,
+                "organization_id": orgID,
+                "deleted":         bson.M{"$ne": true},
+            }).
+            SetUpdate(bson.M{"$set": bson.M{"position": i, "updated_at": now}}))
+    }
+
+    // Reject the whole order if any ID isn't one of the organization's
+    // services, rather than applying part of it
+    ok, err := servicesBelongTo(orgID, req.ServiceIDs)
+    if err != nil {
+        log.Printf("Error checking services: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Service not found"})
+        return
+    }
+
+    if len(writes) > 0 {
+        collection := database.GetCollection("services")
+        result, err := collection.BulkWrite(context.TODO(), writes)
+        if err != nil {
+            log.Printf("Error reordering services: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder services"})
+            return
+        }
+        if result.MatchedCount != int64(len(writes)) {
+            // A service was deleted while reordering
+            c.JSON(http.StatusConflict, gin.H{"error": "Services changed, try again"})
+            return
+        }
+    }
+
+    serviceIDs := make([]string, len(req.ServiceIDs))
+    for i, id := range req.ServiceIDs {
+        serviceIDs[i] = id.Hex()
+    }
+
+    // Broadcast new ordering via WebSocket
     websocketMessage := websocket.Message{
-        Type: "status_update",
+        Type: "services_reordered",
         Data: map[string]interface{}{
-            "service_id":      serviceID,
-            "service_name":    existingService.Name,
-            "old_status":      string(existingService.Status),
-            "new_status":      string(update.Status),
-            "message":         update.Message,
-            "organization_id": existingService.OrganizationID.Hex(),
-            "action":          "service_status_updated",
-            "timestamp":       time.Now().Unix(),
+            "service_ids":     serviceIDs,
+            "organization_id": orgID.Hex(),
+            "action":          "services_reordered",
+            "timestamp":       now.Unix(),
         },
     }
     BroadcastWebSocket(c, websocketMessage)
 
-    log.Printf("✅ Service status updated: %s -> %s", existingService.Name, update.Status)
+    log.Printf("✅ Services reordered: %d services", len(serviceIDs))
+    c.JSON(http.StatusOK, gin.H{"message": "Services reordered successfully"})
+}
+
+func UpdateServiceStatus(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    var update struct {
+        Status  models.ServiceStatus `json:"status"`
+        Message string               `json:"message"`
+    }
+
+    if err := c.ShouldBindJSON(&update); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    if !update.Status.Valid() {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status: " + string(update.Status)})
+        return
+    }
+
+    _, err = ApplyServiceStatus(websocketHub(c), orgID, objID, update.Status, update.Message, c.GetString("user_id"))
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        } else {
+            log.Printf("Error updating service status: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
+        }
+        return
+    }
+
     c.JSON(http.StatusOK, gin.H{"message": "Service status updated successfully"})
 }
 
@@ -173,13 +456,20 @@ func DeleteService(c *gin.Context) {
         return
     }
 
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
     collection := database.GetCollection("services")
     
     // Get service info before deletion for broadcasting
     var service models.Service
     err = collection.FindOne(context.TODO(), bson.M{
-        "_id":     objID,
-        "deleted": bson.M{"$ne": true},
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
     }).Decode(&service)
     
     if err != nil {
@@ -195,7 +485,7 @@ func DeleteService(c *gin.Context) {
     // Soft delete: set deleted = true
     _, err = collection.UpdateOne(
         context.TODO(),
-        bson.M{"_id": objID},
+        bson.M{"_id": objID, "organization_id": orgID},
         bson.M{
             "$set": bson.M{
                 "deleted":    true,
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+func TestUpdateServiceRevalidatesMonitorOnURLChange(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+    monitor := models.MonitorConfig{Enabled: true, Type: models.MonitorHTTP}
+    monitor.ApplyDefaults()
+    _, err := database.GetCollection("services").UpdateOne(context.TODO(),
+        bson.M{"_id": service.ID},
+        bson.M{"$set": bson.M{"url": "https://api.example.com", "monitor": monitor}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    r := tenantRouter(orgID, models.RoleEditor)
+    r.PATCH("/services/:id", UpdateService)
+    path := "/services/" + service.ID.Hex()
+
+    if w := doJSON(r, http.MethodPatch, path, map[string]interface{}{"url": "ftp://api.example.com"}); w.Code != http.StatusBadRequest {
+        t.Errorf("URL the monitor can't probe = %d, want 400: %s", w.Code, w.Body)
+    }
+    if got := findService(t, service.ID).URL; got != "https://api.example.com" {
+        t.Errorf("url = %q after a rejected update", got)
+    }
+
+    if w := doJSON(r, http.MethodPatch, path, map[string]interface{}{"url": "https://status.example.com/health"}); w.Code != http.StatusOK {
+        t.Errorf("valid URL = %d: %s", w.Code, w.Body)
+    }
+    if w := doJSON(r, http.MethodPatch, path, map[string]interface{}{"monitor": map[string]interface{}{"enabled": true, "type": "http", "method": "DELETE"}}); w.Code != http.StatusBadRequest {
+        t.Errorf("DELETE monitor = %d, want 400: %s", w.Code, w.Body)
+    }
+}
+
+func TestReorderServicesRejectsForeignIDs(t *testing.T) {
+    setupTestDB(t)
+    orgA := primitive.NewObjectID()
+    orgB := primitive.NewObjectID()
+    first := insertService(t, orgA, "API")
+    second := insertService(t, orgA, "Web")
+    theirs := insertService(t, orgB, "Their API")
+
+    r := tenantRouter(orgA, models.RoleEditor)
+    r.PUT("/services/order", ReorderServices)
+
+    tests := []struct {
+        name string
+        ids  []primitive.ObjectID
+    }{
+        {"another organization's service", []primitive.ObjectID{second.ID, theirs.ID, first.ID}},
+        {"unknown service", []primitive.ObjectID{second.ID, primitive.NewObjectID(), first.ID}},
+    }
+    for _, tt := range tests {
+        w := doJSON(r, http.MethodPut, "/services/order", map[string]interface{}{"service_ids": tt.ids})
+        if w.Code != http.StatusBadRequest {
+            t.Errorf("%s: status = %d, want 400: %s", tt.name, w.Code, w.Body)
+        }
+    }
+    for _, service := range []models.Service{first, second, theirs} {
+        if got := findService(t, service.ID); got.Position != service.Position {
+            t.Errorf("%s moved to %d", service.Name, got.Position)
+        }
+    }
+
+    w := doJSON(r, http.MethodPut, "/services/order", map[string]interface{}{"service_ids": []primitive.ObjectID{second.ID, first.ID}})
+    if w.Code != http.StatusOK {
+        t.Fatalf("valid order = %d: %s", w.Code, w.Body)
+    }
+    if findService(t, second.ID).Position != 0 || findService(t, first.ID).Position != 1 {
+        t.Errorf("services not reordered")
+    }
+}
\ No newline at end of file
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+const (
+    minSlugLength = 3
+    maxSlugLength = 63
+)
+
+var (
+    slugPattern   = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+    slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// reservedSlugs can't be used by organizations because they collide with
+// routes or would be misleading on a public status page
+var reservedSlugs = map[string]bool{
+    "admin":     true,
+    "api":       true,
+    "app":       true,
+    "assets":    true,
+    "dashboard": true,
+    "docs":      true,
+    "health":    true,
+    "help":      true,
+    "login":     true,
+    "public":    true,
+    "settings":  true,
+    "sign-in":   true,
+    "sign-up":   true,
+    "static":    true,
+    "status":    true,
+    "support":   true,
+    "www":       true,
+    "ws":        true,
+}
+
+// validSlug reports whether s is lowercase letters, digits and single hyphens
+func validSlug(s string) bool {
+    return len(s) >= minSlugLength && len(s) <= maxSlugLength && slugPattern.MatchString(s)
+}
+
+// slugProblem explains why s can't be used as a slug, or returns "" if it can
+func slugProblem(s string) string {
+    if !validSlug(s) {
+        return "Slug must be 3-63 lowercase letters, digits or hyphens"
+    }
+    if reservedSlugs[s] {
+        return "Slug is reserved"
+    }
+    return ""
+}
+
+// slugTaken reports whether another organization uses slug, either currently
+// or as a previous slug that still redirects
+func slugTaken(slug string, exclude primitive.ObjectID) (bool, error) {
+    collection := database.GetCollection("organizations")
+    count, err := collection.CountDocuments(context.TODO(), bson.M{
+        "_id": bson.M{"$ne": exclude},
+        "$or": []bson.M{
+            {"slug": slug},
+            {"previous_slugs": slug},
+        },
+    })
+    return count > 0, err
+}
+
+// slugify derives a slug from an organization name, e.g. "Acme, Inc." -> "acme-inc"
+func slugify(name string) string {
+    slug := slugSeparator.ReplaceAllString(strings.ToLower(name), "-")
+    slug = strings.Trim(slug, "-")
+    if len(slug) > maxSlugLength {
+        slug = strings.TrimRight(slug[:maxSlugLength], "-")
+    }
+    return slug
+}
+
+// availableSlug returns base, or base with a numeric suffix if it is taken
+func availableSlug(base string) (string, error) {
+    slug := base
+    for i := 2; ; i++ {
+        if !reservedSlugs[slug] {
+            taken, err := slugTaken(slug, primitive.NilObjectID)
+            if err != nil {
+                return "", err
+            }
+            if !taken {
+                return slug, nil
+            }
+        }
+
+        suffix := "-" + strconv.Itoa(i)
+        if len(base)+len(suffix) > maxSlugLength {
+            base = strings.TrimRight(base[:maxSlugLength-len(suffix)], "-")
+        }
+        slug = base + suffix
+    }
+}
+
+// findPublicOrganization looks up an organization by slug for the public
+// routes. If the slug is a previous one it answers with a 301 to the same
+// route under the current slug. It writes the response and returns false
+// when the handler should stop.
+func findPublicOrganization(c *gin.Context) (models.Organization, bool) {
+    slug := c.Param("slug")
+
+    // Find organization by slug (exclude deleted orgs)
+    collection := database.GetCollection("organizations")
+    var org models.Organization
+    err := collection.FindOne(context.TODO(), bson.M{
+        "slug":    slug,
+        "deleted": bson.M{"$ne": true}, // Exclude where deleted=true, include where deleted field doesn't exist
+    }).Decode(&org)
+    if err == nil {
+        return org, true
+    }
+    if err != mongo.ErrNoDocuments {
+        log.Printf("Error finding organization: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return org, false
+    }
+
+    // Fall back to slug history so old links keep working
+    err = collection.FindOne(context.TODO(), bson.M{
+        "previous_slugs": slug,
+        "deleted":        bson.M{"$ne": true},
+    }).Decode(&org)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+        } else {
+            log.Printf("Error finding organization by previous slug: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return org, false
+    }
+
+    location := strings.Replace(c.Request.URL.Path, "/status/"+slug, "/status/"+org.Slug, 1)
+    if c.Request.URL.RawQuery != "" {
+        location += "?" + c.Request.URL.RawQuery
+    }
+
+    c.Header("Location", location)
+    c.JSON(http.StatusMovedPermanently, gin.H{
+        "error":    "Organization has moved",
+        "slug":     org.Slug,
+        "location": location,
+    })
+    return org, false
+}
\ No newline at end of file
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+
+    "status-page-backend/database"
+)
+
+func TestSlugify(t *testing.T) {
+    tests := []struct {
+        name string
+        want string
+    }{
+        {"Acme", "acme"},
+        {"Acme, Inc.", "acme-inc"},
+        {"  --Acme   Cloud--  ", "acme-cloud"},
+        {"Ünïcode Café", "n-code-caf"},
+        {"Status 2.0", "status-2-0"},
+        {"!!!", ""},
+        {strings.Repeat("a", 62) + " b", strings.Repeat("a", 62)},
+        {strings.Repeat("ab", 40), strings.Repeat("ab", 31) + "a"},
+    }
+
+    for _, tt := range tests {
+        if got := slugify(tt.name); got != tt.want {
+            t.Errorf("slugify(%q) = %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestSlugProblem(t *testing.T) {
+    tests := []struct {
+        slug string
+        ok   bool
+    }{
+        {"acme", true},
+        {"acme-cloud-2", true},
+        {"abc", true},
+        {strings.Repeat("a", maxSlugLength), true},
+        {"", false},
+        {"ab", false},
+        {strings.Repeat("a", maxSlugLength+1), false},
+        {"Acme", false},
+        {"acme_cloud", false},
+        {"acme--cloud", false},
+        {"-acme", false},
+        {"acme-", false},
+        {"acme cloud", false},
+        {"api", false},
+        {"status", false},
+        {"sign-in", false},
+        {"status-2", true},
+    }
+
+    for _, tt := range tests {
+        if got := slugProblem(tt.slug); (got == "") != tt.ok {
+            t.Errorf("slugProblem(%q) = %q, want ok = %v", tt.slug, got, tt.ok)
+        }
+    }
+}
+
+func TestAvailableSlug(t *testing.T) {
+    setupTestDB(t)
+
+    org := insertOrganization(t, "acme")
+    if slug, err := availableSlug("globex"); err != nil || slug != "globex" {
+        t.Fatalf("availableSlug(globex) = %q, %v", slug, err)
+    }
+    if slug, err := availableSlug(slugify("Acme")); err != nil || slug != "acme-2" {
+        t.Fatalf("availableSlug(acme) = %q, %v, want acme-2", slug, err)
+    }
+
+    insertOrganization(t, "acme-2")
+    if slug, _ := availableSlug("acme"); slug != "acme-3" {
+        t.Fatalf("availableSlug(acme) = %q, want acme-3", slug)
+    }
+
+    // Reserved names are skipped even though nobody uses them
+    if slug, _ := availableSlug(slugify("Status")); slug != "status-2" {
+        t.Fatalf("availableSlug(status) = %q, want status-2", slug)
+    }
+
+    // Excluding an organization frees its own slug
+    if taken, err := slugTaken("acme", org.ID); err != nil || taken {
+        t.Fatalf("slugTaken(acme, self) = %v, %v", taken, err)
+    }
+
+    // Suffixes still fit in the length limit
+    long := strings.Repeat("a", maxSlugLength)
+    insertOrganization(t, long)
+    slug, err := availableSlug(long)
+    if err != nil || slug != strings.Repeat("a", maxSlugLength-2)+"-2" {
+        t.Fatalf("availableSlug(long) = %q, %v", slug, err)
+    }
+    if slugProblem(slug) != "" {
+        t.Fatalf("suffixed slug %q is not valid", slug)
+    }
+}
+
+func TestFindPublicOrganizationRedirectsPreviousSlugs(t *testing.T) {
+    setupTestDB(t)
+    org := insertOrganization(t, "acme")
+    retired := insertOrganization(t, "globex")
+    _, err := database.GetCollection("organizations").UpdateOne(context.TODO(),
+        bson.M{"_id": org.ID},
+        bson.M{"$set": bson.M{"previous_slugs": []string{"acme-old"}}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    _, err = database.GetCollection("organizations").UpdateOne(context.TODO(),
+        bson.M{"_id": retired.ID},
+        bson.M{"$set": bson.M{"previous_slugs": []string{"globex-old"}, "deleted": true}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // A previous slug is still taken so nobody can hijack old links
+    if taken, _ := slugTaken("acme-old", retired.ID); !taken {
+        t.Error("previous slug acme-old is available to other organizations")
+    }
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    handler := func(c *gin.Context) {
+        found, ok := findPublicOrganization(c)
+        if !ok {
+            return
+        }
+        c.JSON(http.StatusOK, gin.H{"slug": found.Slug})
+    }
+    r.GET("/status/:slug", handler)
+    r.GET("/status/:slug/incidents", handler)
+
+    tests := []struct {
+        path     string
+        want     int
+        location string
+    }{
+        {"/status/acme", http.StatusOK, ""},
+        {"/status/acme-old", http.StatusMovedPermanently, "/status/acme"},
+        {"/status/acme-old/incidents?limit=5", http.StatusMovedPermanently, "/status/acme/incidents?limit=5"},
+        {"/status/globex", http.StatusNotFound, ""},
+        {"/status/globex-old", http.StatusNotFound, ""},
+        {"/status/nobody", http.StatusNotFound, ""},
+    }
+
+    for _, tt := range tests {
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+        if w.Code != tt.want {
+            t.Errorf("%s: status = %d, want %d: %s", tt.path, w.Code, tt.want, w.Body)
+            continue
+        }
+        if got := w.Header().Get("Location"); got != tt.location {
+            t.Errorf("%s: Location = %q, want %q", tt.path, got, tt.location)
+        }
+        if tt.location != "" && !strings.Contains(w.Body.String(), `"slug":"acme"`) {
+            t.Errorf("%s: redirect body lacks the current slug: %s", tt.path, w.Body)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+// ApplyServiceStatus moves a service to a new status, records the transition
+// in status_history and broadcasts it. It is the single code path for status
+// changes, whether they come from a user or a background job. It returns the
+// service as it was before the change.
+func ApplyServiceStatus(hub *websocket.Hub, orgID, serviceID primitive.ObjectID, status models.ServiceStatus, message, actor string) (models.Service, error) {
+    return ReplaceServiceStatus(hub, orgID, serviceID, "", status, message, actor)
+}
+
+// ReplaceServiceStatus is ApplyServiceStatus for changes that should only
+// happen while the service is still in status from, e.g. undoing a change
+// nobody has overridden since. An empty from matches any status. It returns
+// mongo.ErrNoDocuments if the service has moved on.
+func ReplaceServiceStatus(hub *websocket.Hub, orgID, serviceID primitive.ObjectID, from, status models.ServiceStatus, message, actor string) (models.Service, error) {
+    now := time.Now()
+
+    filter := bson.M{
+        "_id":             serviceID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }
+    if from != "" {
+        filter["status"] = from
+    }
+
+    collection := database.GetCollection("services")
+    var existingService models.Service
+    err := collection.FindOneAndUpdate(
+        context.TODO(),
+        filter,
+        bson.M{
+            "$set": bson.M{
+                "status":     status,
+                "updated_at": now,
+            },
+        },
+        options.FindOneAndUpdate().SetReturnDocument(options.Before),
+    ).Decode(&existingService)
+    if err != nil {
+        return existingService, err
+    }
+
+    if existingService.Status != status {
+        recordStatusChange(models.StatusChange{
+            OrganizationID: orgID,
+            ServiceID:      serviceID,
+            OldStatus:      existingService.Status,
+            NewStatus:      status,
+            Message:        message,
+            Actor:          actor,
+            CreatedAt:      now,
+        })
+    }
+
+    // Broadcast status update via WebSocket
+    websocketMessage := websocket.Message{
+        Type: "status_update",
+        Data: map[string]interface{}{
+            "service_id":      serviceID.Hex(),
+            "service_name":    existingService.Name,
+            "old_status":      string(existingService.Status),
+            "new_status":      string(status),
+            "message":         message,
+            "organization_id": orgID.Hex(),
+            "action":          "service_status_updated",
+            "timestamp":       now.Unix(),
+        },
+    }
+    Broadcast(hub, websocketMessage)
+
+    log.Printf("✅ Service status updated: %s -> %s (%s)", existingService.Name, status, actor)
+    return existingService, nil
+}
+
+// recordStatusChange stores a transition. Failures are only logged since the
+// service itself has already been updated.
+func recordStatusChange(change models.StatusChange) {
+    if _, err := database.GetCollection("status_history").InsertOne(context.TODO(), change); err != nil {
+        log.Printf("Error recording status change for service %s: %v", change.ServiceID.Hex(), err)
+    }
+}
+
+func GetServiceHistory(c *gin.Context) {
+    serviceID := c.Param("id")
+    objID, err := primitive.ObjectIDFromHex(serviceID)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+    if err != nil || page < 1 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page"})
+        return
+    }
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+    if err != nil || limit < 1 || limit > 200 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be between 1 and 200"})
+        return
+    }
+
+    filter := bson.M{
+        "service_id":      objID,
+        "organization_id": orgID,
+    }
+
+    createdAt := bson.M{}
+    if from := c.Query("from"); from != "" {
+        t, err := time.Parse(time.RFC3339, from)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 time"})
+            return
+        }
+        createdAt["$gte"] = t
+    }
+    if to := c.Query("to"); to != "" {
+        t, err := time.Parse(time.RFC3339, to)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 time"})
+            return
+        }
+        createdAt["$lt"] = t
+    }
+    if len(createdAt) > 0 {
+        filter["created_at"] = createdAt
+    }
+
+    // History of deleted services is still available
+    count, err := database.GetCollection("services").CountDocuments(context.TODO(), bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+    })
+    if err != nil {
+        log.Printf("Error finding service: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        return
+    }
+    if count == 0 {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        return
+    }
+
+    collection := database.GetCollection("status_history")
+    total, err := collection.CountDocuments(context.TODO(), filter)
+    if err != nil {
+        log.Printf("Error counting status history: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+        return
+    }
+
+    findOptions := options.Find().
+        SetSort(bson.D{{Key: "created_at", Value: -1}}).
+        SetSkip(int64((page - 1) * limit)).
+        SetLimit(int64(limit))
+
+    cursor, err := collection.Find(context.TODO(), filter, findOptions)
+    if err != nil {
+        log.Printf("Error finding status history: %v", err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch history"})
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    history := make([]models.StatusChange, 0)
+    if err := cursor.All(context.TODO(), &history); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode history"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "history": history,
+        "page":    page,
+        "limit":   limit,
+        "total":   total,
+    })
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+    "net/http"
+    "reflect"
+    "testing"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/models"
+)
+
+// Every tenant-scoped mutator must treat another organization's IDs as
+// missing and leave that organization's documents untouched
+func TestMutatorsIgnoreOtherOrganizations(t *testing.T) {
+    setupTestDB(t)
+    orgA := primitive.NewObjectID()
+    orgB := primitive.NewObjectID()
+
+    serviceB := insertService(t, orgB, "Their API")
+    incidentB := insertIncident(t, orgB, "Their outage", serviceB.ID)
+    serviceBefore := findService(t, serviceB.ID)
+    incidentBefore := findIncident(t, incidentB.ID)
+
+    r := tenantRouter(orgA, models.RoleOwner)
+    r.PUT("/services/:id", UpdateService)
+    r.PATCH("/services/:id", UpdateService)
+    r.PUT("/services/:id/status", UpdateServiceStatus)
+    r.POST("/services/:id/heartbeat-token", CreateHeartbeatToken)
+    r.DELETE("/services/:id", DeleteService)
+    r.PUT("/incidents/:id", UpdateIncident)
+    r.PATCH("/incidents/:id", UpdateIncident)
+    r.POST("/incidents/:id/updates", CreateIncidentUpdate)
+    r.PUT("/incidents/:id/postmortem", UpdatePostmortem)
+
+    service := "/services/" + serviceB.ID.Hex()
+    incident := "/incidents/" + incidentB.ID.Hex()
+    tests := []struct {
+        method string
+        path   string
+        body   map[string]interface{}
+    }{
+        {http.MethodPut, service, map[string]interface{}{"name": "Taken over"}},
+        {http.MethodPatch, service, map[string]interface{}{"description": "Taken over"}},
+        {http.MethodPut, service + "/status", map[string]interface{}{"status": "major_outage"}},
+        {http.MethodPost, service + "/heartbeat-token", nil},
+        {http.MethodDelete, service, nil},
+        {http.MethodPut, incident, map[string]interface{}{"title": "Taken over", "status": "resolved"}},
+        {http.MethodPatch, incident, map[string]interface{}{"status": "resolved"}},
+        {http.MethodPost, incident + "/updates", map[string]interface{}{"status": "monitoring", "message": "Taken over"}},
+        {http.MethodPut, incident + "/postmortem", map[string]interface{}{"body": "Taken over"}},
+    }
+    for _, tt := range tests {
+        w := doJSON(r, tt.method, tt.path, tt.body)
+        if w.Code != http.StatusNotFound {
+            t.Errorf("%s %s = %d, want 404: %s", tt.method, tt.path, w.Code, w.Body)
+        }
+    }
+
+    if after := findService(t, serviceB.ID); !reflect.DeepEqual(after, serviceBefore) {
+        t.Errorf("service changed:\nbefore %+v\nafter  %+v", serviceBefore, after)
+    }
+    if after := findIncident(t, incidentB.ID); !reflect.DeepEqual(after, incidentBefore) {
+        t.Errorf("incident changed:\nbefore %+v\nafter  %+v", incidentBefore, after)
+    }
+}
+
+// An incident can't pull another organization's services into its impact
+func TestIncidentsRejectOtherOrganizationsServices(t *testing.T) {
+    setupTestDB(t)
+    orgA := primitive.NewObjectID()
+    orgB := primitive.NewObjectID()
+
+    serviceB := insertService(t, orgB, "Their API")
+    incidentA := insertIncident(t, orgA, "Our outage")
+    serviceBefore := findService(t, serviceB.ID)
+
+    r := tenantRouter(orgA, models.RoleOwner)
+    r.POST("/incidents", CreateIncident)
+    r.PATCH("/incidents/:id", UpdateIncident)
+
+    impact := []map[string]interface{}{{"service_id": serviceB.ID.Hex(), "status": "major_outage"}}
+    w := doJSON(r, http.MethodPost, "/incidents", map[string]interface{}{
+        "title":             "Their outage",
+        "affected_services": []string{serviceB.ID.Hex()},
+        "service_statuses":  impact,
+    })
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("POST /incidents = %d, want 400: %s", w.Code, w.Body)
+    }
+    w = doJSON(r, http.MethodPatch, "/incidents/"+incidentA.ID.Hex(), map[string]interface{}{
+        "affected_services": []string{serviceB.ID.Hex()},
+        "service_statuses":  impact,
+    })
+    if w.Code != http.StatusBadRequest {
+        t.Errorf("PATCH /incidents/:id = %d, want 400: %s", w.Code, w.Body)
+    }
+
+    if after := findService(t, serviceB.ID); !reflect.DeepEqual(after, serviceBefore) {
+        t.Errorf("service changed:\nbefore %+v\nafter  %+v", serviceBefore, after)
+    }
+}
\ No newline at end of file
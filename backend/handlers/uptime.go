@@ -0,0 +1,125 @@
+package handlers
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/uptime"
+)
+
+// serviceUptime computes uptime for each named window ending at now. Windows
+// never start before the service was created.
+func serviceUptime(service models.Service, windows map[string]time.Duration, now time.Time) (map[string]uptime.Result, error) {
+    var longest time.Duration
+    for _, d := range windows {
+        if d > longest {
+            longest = d
+        }
+    }
+
+    start := now.Add(-longest)
+    if service.CreatedAt.After(start) {
+        start = service.CreatedAt
+    }
+
+    initial, changes, err := uptime.ChangesBetween(service, start, now)
+    if err != nil {
+        return nil, err
+    }
+
+    results := make(map[string]uptime.Result, len(windows))
+    for name, d := range windows {
+        from := now.Add(-d)
+        if from.Before(start) {
+            from = start
+        }
+        results[name] = uptime.Calculate(initial, changes, from, now, uptime.DefaultWeights)
+    }
+    return results, nil
+}
+
+func GetServiceUptime(c *gin.Context) {
+    objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+        return
+    }
+
+    orgID, err := primitive.ObjectIDFromHex(c.GetString("organization_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+        return
+    }
+
+    now := time.Now()
+    windows := make(map[string]time.Duration)
+
+    // Windows end at ?to, or now if it is left out or in the future
+    if toParam := c.Query("to"); toParam != "" {
+        to, err := time.Parse(time.RFC3339, toParam)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 time"})
+            return
+        }
+        if to.Before(now) {
+            now = to
+        }
+    }
+
+    // Either a named window or an explicit from/to range
+    if fromParam := c.Query("from"); fromParam != "" {
+        from, err := time.Parse(time.RFC3339, fromParam)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 time"})
+            return
+        }
+        if !from.Before(now) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+            return
+        }
+        windows["custom"] = now.Sub(from)
+    } else if name := c.Query("window"); name != "" {
+        d, ok := uptime.Windows[name]
+        if !ok {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Window must be one of day, week, month or 90d"})
+            return
+        }
+        windows[name] = d
+    } else {
+        windows = uptime.Windows
+    }
+
+    var service models.Service
+    err = database.GetCollection("services").FindOne(context.TODO(), bson.M{
+        "_id":             objID,
+        "organization_id": orgID,
+        "deleted":         bson.M{"$ne": true},
+    }).Decode(&service)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+        } else {
+            log.Printf("Error finding service: %v", err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+        }
+        return
+    }
+
+    results, err := serviceUptime(service, windows, now)
+    if err != nil {
+        log.Printf("Error computing uptime for %s: %v", service.Name, err)
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute uptime"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"service_id": service.ID, "uptime": results})
+}
\ No newline at end of file
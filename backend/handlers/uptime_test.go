@@ -0,0 +1,72 @@
+package handlers
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/uptime"
+)
+
+func TestGetServiceUptimeHonoursToWithoutFrom(t *testing.T) {
+    setupTestDB(t)
+    orgID := primitive.NewObjectID()
+    service := insertService(t, orgID, "API")
+    now := time.Now().Truncate(time.Second)
+    _, err := database.GetCollection("services").UpdateOne(context.TODO(),
+        bson.M{"_id": service.ID},
+        bson.M{"$set": bson.M{"created_at": now.Add(-72 * time.Hour)}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // A four hour outage that ended more than a day ago
+    changes := []interface{}{
+        models.StatusChange{OrganizationID: orgID, ServiceID: service.ID, OldStatus: models.StatusOperational, NewStatus: models.StatusMajorOutage, CreatedAt: now.Add(-36 * time.Hour)},
+        models.StatusChange{OrganizationID: orgID, ServiceID: service.ID, OldStatus: models.StatusMajorOutage, NewStatus: models.StatusOperational, CreatedAt: now.Add(-32 * time.Hour)},
+    }
+    if _, err := database.GetCollection("status_history").InsertMany(context.TODO(), changes); err != nil {
+        t.Fatal(err)
+    }
+
+    r := tenantRouter(orgID, models.RoleViewer)
+    r.GET("/services/:id/uptime", GetServiceUptime)
+    get := func(query string) map[string]uptime.Result {
+        t.Helper()
+        w := doJSON(r, http.MethodGet, "/services/"+service.ID.Hex()+"/uptime?"+query, nil)
+        if w.Code != http.StatusOK {
+            t.Fatalf("uptime?%s = %d: %s", query, w.Code, w.Body)
+        }
+        var body struct {
+            Uptime map[string]uptime.Result `json:"uptime"`
+        }
+        json.Unmarshal(w.Body.Bytes(), &body)
+        return body.Uptime
+    }
+
+    if got := get("window=day")["day"]; got.Percentage != 100 {
+        t.Errorf("last day = %v%%, want 100", got.Percentage)
+    }
+
+    to := now.Add(-24 * time.Hour)
+    got := get("window=day&to=" + url.QueryEscape(to.Format(time.RFC3339)))["day"]
+    if !got.To.Equal(to) || !got.From.Equal(to.Add(-24*time.Hour)) {
+        t.Errorf("window = %v - %v, want the day before %v", got.From, got.To, to)
+    }
+    if got.Downtime != (4 * time.Hour).Seconds() {
+        t.Errorf("downtime = %vs, want the 4h outage", got.Downtime)
+    }
+
+    if _, ok := get("to=" + url.QueryEscape(to.Format(time.RFC3339)))["week"]; !ok {
+        t.Error("to alone should still return every named window")
+    }
+}
\ No newline at end of file
@@ -1,22 +1,63 @@
 package handlers
 
 import (
+    "crypto/sha256"
+    "encoding/hex"
     "log"
 
     "github.com/gin-gonic/gin"
+    "status-page-backend/models"
     "status-page-backend/websocket"
 )
 
 // BroadcastWebSocket is a shared helper function to broadcast WebSocket messages
 func BroadcastWebSocket(c *gin.Context, message websocket.Message) {
-    if hub, exists := c.Get("websocket_hub"); exists {
-        if wsHub, ok := hub.(*websocket.Hub); ok {
-            log.Printf("📡 Broadcasting WebSocket message: %s", message.Type)
-            wsHub.Broadcast(message)
-        } else {
-            log.Printf("❌ WebSocket hub type assertion failed")
-        }
-    } else {
+    Broadcast(websocketHub(c), message)
+}
+
+// Broadcast sends a message through the hub, for callers outside a request
+// such as background jobs
+func Broadcast(hub *websocket.Hub, message websocket.Message) {
+    if hub == nil {
+        log.Printf("❌ WebSocket hub not available, dropping message: %s", message.Type)
+        return
+    }
+    log.Printf("📡 Broadcasting WebSocket message: %s", message.Type)
+    hub.Broadcast(message)
+}
+
+// websocketHub returns the hub stored in the request context, or nil
+func websocketHub(c *gin.Context) *websocket.Hub {
+    hub, exists := c.Get("websocket_hub")
+    if !exists {
         log.Printf("❌ WebSocket hub not found in context")
+        return nil
+    }
+    wsHub, ok := hub.(*websocket.Hub)
+    if !ok {
+        log.Printf("❌ WebSocket hub type assertion failed")
+        return nil
     }
+    return wsHub
+}
+
+// currentOrganization returns the organization loaded by TenantMiddleware
+func currentOrganization(c *gin.Context) models.Organization {
+    org, _ := c.Get("organization")
+    o, _ := org.(models.Organization)
+    return o
+}
+
+// currentRole returns the caller's role in the current organization
+func currentRole(c *gin.Context) models.Role {
+    role, _ := c.Get("member_role")
+    r, _ := role.(models.Role)
+    return r
+}
+
+// hashToken is how secret tokens are stored, so a database leak doesn't
+// expose usable tokens
+func hashToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package main
 import (
     "log"
     "os"
+    "time"
 
     "github.com/gin-gonic/gin"
     "github.com/gin-contrib/cors"
@@ -10,7 +11,11 @@ import (
 
     "status-page-backend/database"
     "status-page-backend/handlers"
+    "status-page-backend/maintenance"
     "status-page-backend/middleware"
+    "status-page-backend/models"
+    "status-page-backend/monitor"
+    "status-page-backend/rollup"
     "status-page-backend/websocket"
 )
 
@@ -35,19 +40,53 @@ func main() {
     if err := database.ConnectDB(mongoURI, dbName); err != nil {
         log.Fatal("Failed to connect to database:", err)
     }
+    if err := database.EnsureIndexes(); err != nil {
+        log.Fatal("Failed to create database indexes:", err)
+    }
+
+    // Load token verification keys
+    keySet, err := middleware.LoadKeySet(os.Getenv("AUTH_JWKS_URL"), os.Getenv("AUTH_JWKS_FILE"), time.Hour)
+    if err != nil {
+        log.Fatal("Failed to configure authentication:", err)
+    }
+    authConfig := middleware.AuthConfig{
+        Issuer:   os.Getenv("AUTH_ISSUER"),
+        Audience: os.Getenv("AUTH_AUDIENCE"),
+        Leeway:   30 * time.Second,
+    }
+    if err := authConfig.Validate(); err != nil {
+        log.Fatal("Failed to configure authentication:", err)
+    }
+
+    handlers.ConfigureInvites([]byte(os.Getenv("INVITE_SECRET")), 7*24*time.Hour)
 
     // Initialize WebSocket hub
     hub := websocket.NewHub()
     go hub.Run()
     log.Println("✅ WebSocket hub started")
 
+    // Roll status transitions up into daily uptime bars
+    roller := rollup.NewRoller(10 * time.Minute)
+    go roller.Run()
+    log.Println("✅ Daily status rollups started")
+
+    // Run automated service checks
+    scheduler := monitor.NewScheduler(hub, monitor.DefaultCheckers())
+    go scheduler.Run()
+    log.Println("✅ Service monitors started")
+
+    // Start and end scheduled and recurring maintenance windows
+    maintenances := maintenance.NewScheduler(hub, 30*time.Second)
+    go maintenances.Run()
+    log.Println("✅ Maintenance scheduler started")
+
     // Setup Gin router
     r := gin.Default()
 
     // CORS middleware
     r.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"*"}, 
-        AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+        AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
         AllowHeaders:     []string{"*"},
         AllowCredentials: true,
     }))
@@ -76,27 +115,92 @@ func main() {
     public := r.Group("/api/public")
     {
         public.GET("/status/:slug", handlers.GetPublicStatus)
+        public.GET("/status/:slug/history", handlers.GetPublicStatusHistory)
+        public.GET("/status/:slug/incidents", handlers.GetPublicIncidents)
+        public.GET("/status/:slug/incidents/:id", handlers.GetPublicIncident)
     }
 
+    // Heartbeat pings from push monitors, guarded by the token in the URL
+    r.POST("/api/heartbeat/:token", handlers.ReceiveHeartbeat)
+
     // Protected API routes
     api := r.Group("/api")
-    api.Use(middleware.AuthMiddleware())
-    api.Use(middleware.TenantMiddleware())
+    api.Use(middleware.AuthMiddleware(keySet, authConfig))
     {
-        // Organization routes
+        // Organization routes (not scoped to a tenant)
         api.GET("/organizations", handlers.GetOrganizations)
         api.POST("/organizations", handlers.CreateOrganization)
+        api.POST("/invites/accept", handlers.AcceptInvite)
+    }
 
+    // Routes for a single organization, caller must be a member of :id
+    org := api.Group("/organizations/:id")
+    org.Use(middleware.OrganizationMiddleware("id"))
+    {
+        org.GET("", handlers.GetOrganization)
+        org.PUT("", middleware.RequirePermission(models.PermOrganizationWrite), handlers.UpdateOrganization)
+        org.DELETE("", middleware.RequirePermission(models.PermOrganizationDelete), handlers.DeleteOrganization)
+
+        // Member routes
+        org.GET("/members", handlers.GetMembers)
+        org.POST("/invites", middleware.RequirePermission(models.PermMembersManage), handlers.CreateInvite)
+        org.PUT("/members/:user_id", middleware.RequirePermission(models.PermMembersManage), handlers.UpdateMemberRole)
+        org.DELETE("/members/:user_id", middleware.RequirePermission(models.PermMembersManage), handlers.RemoveMember)
+    }
+
+    // Tenant-scoped routes, caller must be a member of X-Organization-ID
+    tenant := api.Group("")
+    tenant.Use(middleware.TenantMiddleware())
+    {
         // Service routes
-        api.GET("/services", handlers.GetServices)
-        api.POST("/services", handlers.CreateService)
-        api.PUT("/services/:id/status", handlers.UpdateServiceStatus) 
-        api.DELETE("/services/:id", handlers.DeleteService)
+        tenant.GET("/services", middleware.RequirePermission(models.PermServicesRead), handlers.GetServices)
+        tenant.POST("/services", middleware.RequirePermission(models.PermServicesWrite), handlers.CreateService)
+        tenant.PUT("/services/order", middleware.RequirePermission(models.PermServicesWrite), handlers.ReorderServices)
+        tenant.PUT("/services/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.UpdateService)
+        tenant.PATCH("/services/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.UpdateService)
+        tenant.PUT("/services/:id/status", middleware.RequirePermission(models.PermServicesWrite), handlers.UpdateServiceStatus)
+        tenant.GET("/services/:id/history", middleware.RequirePermission(models.PermServicesRead), handlers.GetServiceHistory)
+        tenant.GET("/services/:id/uptime", middleware.RequirePermission(models.PermServicesRead), handlers.GetServiceUptime)
+        tenant.GET("/services/:id/latency", middleware.RequirePermission(models.PermServicesRead), handlers.GetServiceLatency)
+        tenant.POST("/services/:id/heartbeat-token", middleware.RequirePermission(models.PermServicesWrite), handlers.CreateHeartbeatToken)
+        tenant.DELETE("/services/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.DeleteService)
+
+        // Service group routes
+        tenant.GET("/service-groups", middleware.RequirePermission(models.PermServicesRead), handlers.GetServiceGroups)
+        tenant.POST("/service-groups", middleware.RequirePermission(models.PermServicesWrite), handlers.CreateServiceGroup)
+        tenant.PUT("/service-groups/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.UpdateServiceGroup)
+        tenant.PATCH("/service-groups/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.UpdateServiceGroup)
+        tenant.DELETE("/service-groups/:id", middleware.RequirePermission(models.PermServicesWrite), handlers.DeleteServiceGroup)
 
         // Incident routes
-        api.GET("/incidents", handlers.GetIncidents)
-        api.POST("/incidents", handlers.CreateIncident)    
-        api.PUT("/incidents/:id", handlers.UpdateIncident) 
+        tenant.GET("/incidents", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetIncidents)
+        tenant.POST("/incidents", middleware.RequirePermission(models.PermIncidentsWrite), handlers.CreateIncident)
+        tenant.PUT("/incidents/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateIncident)
+        tenant.PATCH("/incidents/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateIncident)
+        tenant.POST("/incidents/:id/updates", middleware.RequirePermission(models.PermIncidentsWrite), handlers.CreateIncidentUpdate)
+        tenant.GET("/incidents/:id/postmortem", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetPostmortem)
+        tenant.PUT("/incidents/:id/postmortem", middleware.RequirePermission(models.PermPostmortemsWrite), handlers.UpdatePostmortem)
+        tenant.PATCH("/incidents/:id/postmortem", middleware.RequirePermission(models.PermPostmortemsWrite), handlers.UpdatePostmortem)
+
+        // Incident template routes
+        tenant.GET("/incident-templates", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetIncidentTemplates)
+        tenant.POST("/incident-templates", middleware.RequirePermission(models.PermIncidentsWrite), handlers.CreateIncidentTemplate)
+        tenant.PUT("/incident-templates/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateIncidentTemplate)
+        tenant.PATCH("/incident-templates/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateIncidentTemplate)
+        tenant.DELETE("/incident-templates/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.DeleteIncidentTemplate)
+
+        // Maintenance routes
+        tenant.GET("/maintenances", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetMaintenances)
+        tenant.POST("/maintenances", middleware.RequirePermission(models.PermIncidentsWrite), handlers.CreateMaintenance)
+        tenant.PUT("/maintenances/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateMaintenance)
+        tenant.PATCH("/maintenances/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateMaintenance)
+        tenant.DELETE("/maintenances/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.DeleteMaintenance)
+        tenant.GET("/maintenance-series", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetMaintenanceSeries)
+        tenant.POST("/maintenance-series", middleware.RequirePermission(models.PermIncidentsWrite), handlers.CreateMaintenanceSeries)
+        tenant.PUT("/maintenance-series/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateMaintenanceSeries)
+        tenant.PATCH("/maintenance-series/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.UpdateMaintenanceSeries)
+        tenant.DELETE("/maintenance-series/:id", middleware.RequirePermission(models.PermIncidentsWrite), handlers.DeleteMaintenanceSeries)
+        tenant.GET("/maintenance-series/:id/occurrences", middleware.RequirePermission(models.PermIncidentsRead), handlers.GetMaintenanceSeriesOccurrences)
     }
 
     port := os.Getenv("PORT")
@@ -0,0 +1,111 @@
+package maintenance
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/handlers"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+// expandInterval is how often recurring series are expanded ahead
+const expandInterval = 10 * time.Minute
+
+// Scheduler starts maintenance windows when they are due and completes them
+// when they end. It also expands recurring series into upcoming windows.
+type Scheduler struct {
+    hub      *websocket.Hub
+    interval time.Duration
+
+    lastExpanded time.Time
+}
+
+func NewScheduler(hub *websocket.Hub, interval time.Duration) *Scheduler {
+    return &Scheduler{hub: hub, interval: interval}
+}
+
+func (s *Scheduler) Run() {
+    ticker := time.NewTicker(s.interval)
+    defer ticker.Stop()
+
+    for {
+        s.RunDue(time.Now())
+        <-ticker.C
+    }
+}
+
+// RunDue completes windows that have ended, then starts those that are due
+func (s *Scheduler) RunDue(now time.Time) {
+    if now.Sub(s.lastExpanded) >= expandInterval {
+        s.expandSeries(now)
+        s.lastExpanded = now
+    }
+
+    for _, id := range s.due(bson.M{"status": models.MaintenanceInProgress, "scheduled_end": bson.M{"$lte": now}}) {
+        if err := handlers.CompleteMaintenance(s.hub, id); err != nil {
+            log.Printf("Error completing maintenance %s: %v", id.Hex(), err)
+        }
+    }
+
+    for _, id := range s.due(bson.M{"status": models.MaintenanceScheduled, "scheduled_start": bson.M{"$lte": now}}) {
+        if err := handlers.StartMaintenance(s.hub, id); err != nil {
+            log.Printf("Error starting maintenance %s: %v", id.Hex(), err)
+        }
+    }
+}
+
+func (s *Scheduler) due(filter bson.M) []primitive.ObjectID {
+    filter["deleted"] = bson.M{"$ne": true}
+
+    cursor, err := database.GetCollection("maintenances").Find(context.TODO(), filter)
+    if err != nil {
+        log.Printf("Error finding due maintenances: %v", err)
+        return nil
+    }
+    defer cursor.Close(context.TODO())
+
+    var docs []struct {
+        ID primitive.ObjectID `bson:"_id"`
+    }
+    if err := cursor.All(context.TODO(), &docs); err != nil {
+        log.Printf("Error decoding due maintenances: %v", err)
+        return nil
+    }
+
+    ids := make([]primitive.ObjectID, len(docs))
+    for i, doc := range docs {
+        ids[i] = doc.ID
+    }
+    return ids
+}
+
+// expandSeries makes sure every live series has its windows for the next
+// handlers.SeriesHorizon
+func (s *Scheduler) expandSeries(now time.Time) {
+    cursor, err := database.GetCollection("maintenance_series").Find(context.TODO(), bson.M{
+        "deleted": bson.M{"$ne": true},
+    })
+    if err != nil {
+        log.Printf("Error finding maintenance series: %v", err)
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var series []models.MaintenanceSeries
+    if err := cursor.All(context.TODO(), &series); err != nil {
+        log.Printf("Error decoding maintenance series: %v", err)
+        return
+    }
+
+    for _, ms := range series {
+        if err := handlers.ExpandMaintenanceSeries(ms, now, now.Add(handlers.SeriesHorizon)); err != nil {
+            log.Printf("Error expanding maintenance series %s: %v", ms.Title, err)
+        }
+    }
+}
\ No newline at end of file
@@ -1,50 +1,179 @@
 package middleware
 
 import (
+    "context"
+    "errors"
+    "log"
     "net/http"
     "strings"
+    "time"
 
     "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v5"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
 )
 
-// Simple auth middleware for testing - replace with Clerk later
-func AuthMiddleware() gin.HandlerFunc {
+// AuthConfig describes which tokens AuthMiddleware accepts
+type AuthConfig struct {
+    Issuer   string
+    Audience string
+    Leeway   time.Duration
+}
+
+// Validate checks that tokens are tied to this deployment. Without an issuer
+// and audience, a token the provider issued for any other app would be accepted.
+func (cfg AuthConfig) Validate() error {
+    if cfg.Issuer == "" {
+        return errors.New("AUTH_ISSUER must be set")
+    }
+    if cfg.Audience == "" {
+        return errors.New("AUTH_AUDIENCE must be set")
+    }
+    return nil
+}
+
+// AuthMiddleware verifies RS256/ES256 bearer tokens against the given key set
+// and exposes the subject, email and email_verified claims as user_id,
+// user_email and user_email_verified
+func AuthMiddleware(keys KeySet, cfg AuthConfig) gin.HandlerFunc {
+    parser := jwt.NewParser(parserOptions(cfg)...)
+
     return func(c *gin.Context) {
         authHeader := c.GetHeader("Authorization")
-        
-        // For testing, allow requests without auth or with "Bearer test"
-        if authHeader == "" || authHeader == "Bearer test" {
-            c.Set("user_id", "test_user_123")
-            c.Set("user_email", "test@example.com")
-            c.Next()
+        tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+        if !ok || tokenString == "" {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing bearer token"})
+            c.Abort()
             return
         }
 
-        // Remove Bearer prefix
-        token := strings.Replace(authHeader, "Bearer ", "", 1)
-        
-        // Simple validation for testing
-        if token == "test" {
-            c.Set("user_id", "test_user_123")
-            c.Set("user_email", "test@example.com")
-            c.Next()
+        claims := jwt.MapClaims{}
+        _, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+            kid, _ := token.Header["kid"].(string)
+            return keys.Key(c.Request.Context(), kid)
+        })
+        if err != nil {
+            log.Printf("Rejected token: %v", err)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+            c.Abort()
             return
         }
 
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-        c.Abort()
+        userID, err := claims.GetSubject()
+        if err != nil || userID == "" {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has no subject"})
+            c.Abort()
+            return
+        }
+
+        email, _ := claims["email"].(string)
+
+        c.Set("user_id", userID)
+        c.Set("user_email", email)
+        c.Set("user_email_verified", emailVerified(claims))
+        c.Next()
+    }
+}
+
+// emailVerified reads the email_verified claim, which some providers send
+// as the string "true"
+func emailVerified(claims jwt.MapClaims) bool {
+    switch verified := claims["email_verified"].(type) {
+    case bool:
+        return verified
+    case string:
+        return verified == "true"
+    }
+    return false
+}
+
+func parserOptions(cfg AuthConfig) []jwt.ParserOption {
+    opts := []jwt.ParserOption{
+        jwt.WithValidMethods([]string{"RS256", "ES256"}),
+        jwt.WithExpirationRequired(),
+        jwt.WithLeeway(cfg.Leeway),
+        jwt.WithIssuer(cfg.Issuer),
+        jwt.WithAudience(cfg.Audience),
     }
+    return opts
 }
 
-// Extract organization ID from request
+// LoadKeySet builds a key set from a JWKS URL or, for offline use, a local
+// JWKS file. The URL wins when both are set.
+func LoadKeySet(jwksURL, jwksFile string, ttl time.Duration) (KeySet, error) {
+    if jwksURL != "" {
+        return NewRemoteKeySet(jwksURL, ttl), nil
+    }
+    if jwksFile != "" {
+        return LoadJWKSFile(jwksFile)
+    }
+    return nil, errors.New("either AUTH_JWKS_URL or AUTH_JWKS_FILE must be set")
+}
+
+// TenantMiddleware resolves the organization from the X-Organization-ID header
+// and only lets the request through if the authenticated user is a member
 func TenantMiddleware() gin.HandlerFunc {
+    return resolveTenant(func(c *gin.Context) string {
+        return c.GetHeader("X-Organization-ID")
+    })
+}
+
+// OrganizationMiddleware is TenantMiddleware for routes that carry the
+// organization ID as a path parameter
+func OrganizationMiddleware(param string) gin.HandlerFunc {
+    return resolveTenant(func(c *gin.Context) string {
+        return c.Param(param)
+    })
+}
+
+func resolveTenant(orgIDFrom func(c *gin.Context) string) gin.HandlerFunc {
     return func(c *gin.Context) {
-        // For testing, use a default org ID
-        orgID := c.GetHeader("X-Organization-ID")
+        orgID := orgIDFrom(c)
         if orgID == "" {
-            orgID = "68323d8ecfc5cd8248620005" // Default test org ID
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Missing organization ID"})
+            c.Abort()
+            return
         }
-        c.Set("organization_id", orgID)
+
+        objID, err := primitive.ObjectIDFromHex(orgID)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+            c.Abort()
+            return
+        }
+
+        var org models.Organization
+        err = database.GetCollection("organizations").FindOne(context.TODO(), bson.M{
+            "_id":     objID,
+            "deleted": bson.M{"$ne": true},
+        }).Decode(&org)
+        if err != nil {
+            if err == mongo.ErrNoDocuments {
+                // Don't reveal whether the organization exists
+                c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this organization"})
+            } else {
+                log.Printf("Error loading organization: %v", err)
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+            }
+            c.Abort()
+            return
+        }
+
+        member, ok := org.FindMember(c.GetString("user_id"))
+        if !ok {
+            c.JSON(http.StatusForbidden, gin.H{"error": "Not a member of this organization"})
+            c.Abort()
+            return
+        }
+
+        c.Set("organization_id", org.ID.Hex())
+        c.Set("organization", org)
+        c.Set("member_role", member.Role)
         c.Next()
     }
 }
\ No newline at end of file
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+    "crypto"
+    "crypto/rand"
+    "crypto/rsa"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v5"
+)
+
+var testConfig = AuthConfig{
+    Issuer:   "https://auth.example.com/",
+    Audience: "status-page",
+    Leeway:   30 * time.Second,
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+    if err := testConfig.Validate(); err != nil {
+        t.Errorf("complete config: %v", err)
+    }
+    if err := (AuthConfig{Audience: "status-page"}).Validate(); err == nil {
+        t.Errorf("config without an issuer passed")
+    }
+    if err := (AuthConfig{Issuer: "https://auth.example.com/"}).Validate(); err == nil {
+        t.Errorf("config without an audience passed")
+    }
+}
+
+func TestAuthMiddlewareWithStaticKeySet(t *testing.T) {
+    key, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatal(err)
+    }
+    keys := NewStaticKeySet(map[string]crypto.PublicKey{"key-1": &key.PublicKey})
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/me", AuthMiddleware(keys, testConfig), func(c *gin.Context) {
+        c.String(http.StatusOK, c.GetString("user_id"))
+    })
+
+    valid := func() jwt.MapClaims {
+        return jwt.MapClaims{
+            "sub": "user-1",
+            "iss": testConfig.Issuer,
+            "aud": testConfig.Audience,
+            "exp": time.Now().Add(time.Hour).Unix(),
+        }
+    }
+    sign := func(method jwt.SigningMethod, kid string, claims jwt.MapClaims, signingKey interface{}) string {
+        token := jwt.NewWithClaims(method, claims)
+        token.Header["kid"] = kid
+        signed, err := token.SignedString(signingKey)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return signed
+    }
+    with := func(key string, value interface{}) jwt.MapClaims {
+        claims := valid()
+        claims[key] = value
+        return claims
+    }
+
+    tests := []struct {
+        name  string
+        token string
+        want  int
+    }{
+        {"valid token", sign(jwt.SigningMethodRS256, "key-1", valid(), key), http.StatusOK},
+        {"wrong issuer", sign(jwt.SigningMethodRS256, "key-1", with("iss", "https://evil.example.com/"), key), http.StatusUnauthorized},
+        {"wrong audience", sign(jwt.SigningMethodRS256, "key-1", with("aud", "another-app"), key), http.StatusUnauthorized},
+        {"expired", sign(jwt.SigningMethodRS256, "key-1", with("exp", time.Now().Add(-time.Hour).Unix()), key), http.StatusUnauthorized},
+        {"no expiry", sign(jwt.SigningMethodRS256, "key-1", with("exp", nil), key), http.StatusUnauthorized},
+        {"wrong alg", sign(jwt.SigningMethodHS256, "key-1", valid(), []byte("shared-secret")), http.StatusUnauthorized},
+        {"unlisted RSA alg", sign(jwt.SigningMethodRS512, "key-1", valid(), key), http.StatusUnauthorized},
+        {"unknown kid", sign(jwt.SigningMethodRS256, "key-2", valid(), key), http.StatusUnauthorized},
+        {"no subject", sign(jwt.SigningMethodRS256, "key-1", with("sub", ""), key), http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        req := httptest.NewRequest(http.MethodGet, "/me", nil)
+        req.Header.Set("Authorization", "Bearer "+tt.token)
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+
+        if w.Code != tt.want {
+            t.Errorf("%s: status = %d, want %d: %s", tt.name, w.Code, tt.want, w.Body)
+        }
+        if tt.want == http.StatusOK && w.Body.String() != "user-1" {
+            t.Errorf("%s: user_id = %q", tt.name, w.Body)
+        }
+    }
+}
+
+func TestEmailVerified(t *testing.T) {
+    tests := []struct {
+        claim interface{}
+        want  bool
+    }{
+        {true, true},
+        {"true", true},
+        {false, false},
+        {"false", false},
+        {nil, false},
+        {1, false},
+    }
+    for _, tt := range tests {
+        claims := jwt.MapClaims{"email": "me@example.com"}
+        if tt.claim != nil {
+            claims["email_verified"] = tt.claim
+        }
+        if got := emailVerified(claims); got != tt.want {
+            t.Errorf("email_verified %#v = %v, want %v", tt.claim, got, tt.want)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,256 @@
+package middleware
+
+import (
+    "context"
+    "crypto"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rsa"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "log"
+    "math/big"
+    "net/http"
+    "os"
+    "sync"
+    "time"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeySet resolves the public key used to sign a token by its key ID
+type KeySet interface {
+    Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type jwk struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid"`
+    Use string `json:"use"`
+    Alg string `json:"alg"`
+    N   string `json:"n"`
+    E   string `json:"e"`
+    Crv string `json:"crv"`
+    X   string `json:"x"`
+    Y   string `json:"y"`
+}
+
+// ParseJWKS decodes a JSON Web Key Set into public keys indexed by kid.
+// Keys that are not RSA or EC signing keys are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+    var set struct {
+        Keys []jwk `json:"keys"`
+    }
+    if err := json.Unmarshal(data, &set); err != nil {
+        return nil, fmt.Errorf("invalid JWKS document: %w", err)
+    }
+
+    keys := make(map[string]crypto.PublicKey)
+    for _, k := range set.Keys {
+        if k.Use != "" && k.Use != "sig" {
+            continue
+        }
+
+        key, err := k.publicKey()
+        if err != nil {
+            log.Printf("Skipping JWKS key %q: %v", k.Kid, err)
+            continue
+        }
+        keys[k.Kid] = key
+    }
+
+    return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+    switch k.Kty {
+    case "RSA":
+        n, err := decodeBigInt(k.N)
+        if err != nil {
+            return nil, err
+        }
+        e, err := decodeBigInt(k.E)
+        if err != nil {
+            return nil, err
+        }
+        return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+    case "EC":
+        var curve elliptic.Curve
+        switch k.Crv {
+        case "P-256":
+            curve = elliptic.P256()
+        case "P-384":
+            curve = elliptic.P384()
+        case "P-521":
+            curve = elliptic.P521()
+        default:
+            return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+        }
+        x, err := decodeBigInt(k.X)
+        if err != nil {
+            return nil, err
+        }
+        y, err := decodeBigInt(k.Y)
+        if err != nil {
+            return nil, err
+        }
+        if !curve.IsOnCurve(x, y) {
+            return nil, errors.New("point is not on curve")
+        }
+        return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+    }
+
+    return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+    b, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return nil, err
+    }
+    return new(big.Int).SetBytes(b), nil
+}
+
+// StaticKeySet serves a fixed set of keys, e.g. from a local JWKS file or
+// generated in-process for offline testing
+type StaticKeySet struct {
+    keys map[string]crypto.PublicKey
+}
+
+func NewStaticKeySet(keys map[string]crypto.PublicKey) *StaticKeySet {
+    return &StaticKeySet{keys: keys}
+}
+
+// LoadJWKSFile reads a JWKS document from disk
+func LoadJWKSFile(path string) (*StaticKeySet, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    keys, err := ParseJWKS(data)
+    if err != nil {
+        return nil, err
+    }
+    return NewStaticKeySet(keys), nil
+}
+
+func (s *StaticKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+    if key, ok := s.keys[kid]; ok {
+        return key, nil
+    }
+    return nil, ErrKeyNotFound
+}
+
+// RemoteKeySet fetches keys from a JWKS endpoint and caches them. An unknown
+// kid triggers a refresh so rotated keys are picked up without a restart.
+type RemoteKeySet struct {
+    url        string
+    ttl        time.Duration
+    minRefresh time.Duration
+    client     *http.Client
+
+    mu          sync.Mutex
+    keys        map[string]crypto.PublicKey
+    fetchedAt   time.Time
+    lastAttempt time.Time
+    // refreshing is closed when the refresh in flight finishes
+    refreshing chan struct{}
+}
+
+// jwksFetchTimeout bounds a single fetch of the JWKS document
+const jwksFetchTimeout = 10 * time.Second
+
+func NewRemoteKeySet(url string, ttl time.Duration) *RemoteKeySet {
+    return &RemoteKeySet{
+        url:        url,
+        ttl:        ttl,
+        minRefresh: 30 * time.Second,
+        client:     &http.Client{Timeout: jwksFetchTimeout},
+    }
+}
+
+func (r *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+    r.mu.Lock()
+    expired := time.Since(r.fetchedAt) > r.ttl
+    key, found := r.keys[kid]
+    inFlight := r.refreshing
+
+    // Refresh on expiry or on an unknown kid, but never more often than
+    // minRefresh so a flood of bad tokens can't hammer the provider. The
+    // fetch runs without the lock so cached keys stay available meanwhile.
+    triggered := false
+    if inFlight == nil && (expired || !found) && time.Since(r.lastAttempt) > r.minRefresh {
+        r.lastAttempt = time.Now()
+        inFlight = make(chan struct{})
+        r.refreshing = inFlight
+        triggered = true
+        go r.refresh(inFlight)
+    }
+    r.mu.Unlock()
+
+    // The refresh may bring the key, wait for it
+    if inFlight != nil && (triggered || !found) {
+        select {
+        case <-inFlight:
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        }
+        r.mu.Lock()
+        key, found = r.keys[kid]
+        r.mu.Unlock()
+    }
+
+    if !found {
+        return nil, ErrKeyNotFound
+    }
+    return key, nil
+}
+
+// refresh fetches the keys and closes done. It runs on its own context, so a
+// request that gives up doesn't abort the refresh others are waiting for.
+func (r *RemoteKeySet) refresh(done chan struct{}) {
+    ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+    defer cancel()
+    keys, err := r.fetch(ctx)
+
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if err != nil {
+        log.Printf("Error refreshing JWKS from %s: %v", r.url, err)
+    } else {
+        r.keys = keys
+        r.fetchedAt = time.Now()
+        log.Printf("🔑 Loaded %d signing keys from JWKS", len(keys))
+    }
+    r.refreshing = nil
+    close(done)
+}
+
+func (r *RemoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := r.client.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+    }
+
+    data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+    if err != nil {
+        return nil, err
+    }
+
+    return ParseJWKS(data)
+}
\ No newline at end of file
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "encoding/base64"
+    "fmt"
+    "math/big"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func jwkJSON(kid string, key *rsa.PublicKey) string {
+    return fmt.Sprintf(`{"kty":"RSA","kid":%q,"use":"sig","n":%q,"e":%q}`, kid,
+        base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+        base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+}
+
+// A slow refresh for an unknown kid must not hold up tokens signed with
+// keys that are already cached
+func TestRemoteKeySetRefreshDoesNotBlockCachedKeys(t *testing.T) {
+    key1, _ := rsa.GenerateKey(rand.Reader, 2048)
+    key2, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+    var fetches int32
+    release := make(chan struct{})
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.AddInt32(&fetches, 1) == 1 {
+            fmt.Fprintf(w, `{"keys":[%s]}`, jwkJSON("key-1", &key1.PublicKey))
+            return
+        }
+        <-release
+        fmt.Fprintf(w, `{"keys":[%s,%s]}`, jwkJSON("key-1", &key1.PublicKey), jwkJSON("key-2", &key2.PublicKey))
+    }))
+    defer server.Close()
+
+    keys := NewRemoteKeySet(server.URL, time.Hour)
+    keys.minRefresh = 0
+    ctx := context.Background()
+    if _, err := keys.Key(ctx, "key-1"); err != nil {
+        t.Fatalf("initial fetch: %v", err)
+    }
+
+    // Two requests for the rotated key, one of them refreshes
+    var wg sync.WaitGroup
+    errs := make(chan error, 2)
+    for i := 0; i < 2; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, err := keys.Key(ctx, "key-2")
+            errs <- err
+        }()
+    }
+    for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&fetches) < 2; {
+        if time.Now().After(deadline) {
+            t.Fatal("refresh never started")
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+
+    cached := make(chan error, 1)
+    go func() {
+        _, err := keys.Key(ctx, "key-1")
+        cached <- err
+    }()
+    select {
+    case err := <-cached:
+        if err != nil {
+            t.Errorf("cached key: %v", err)
+        }
+    case <-time.After(time.Second):
+        t.Fatal("cached key blocked behind the refresh")
+    }
+
+    close(release)
+    wg.Wait()
+    close(errs)
+    for err := range errs {
+        if err != nil {
+            t.Errorf("rotated key: %v", err)
+        }
+    }
+    if n := atomic.LoadInt32(&fetches); n != 2 {
+        t.Errorf("fetched %d times, want 2", n)
+    }
+}
+
+// The request that triggers a refresh giving up must not abort it for the
+// others waiting on it
+func TestRemoteKeySetRefreshOutlivesTriggeringRequest(t *testing.T) {
+    key, _ := rsa.GenerateKey(rand.Reader, 2048)
+    release := make(chan struct{})
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        <-release
+        fmt.Fprintf(w, `{"keys":[%s]}`, jwkJSON("key-1", &key.PublicKey))
+    }))
+    defer server.Close()
+
+    keys := NewRemoteKeySet(server.URL, time.Hour)
+    triggerCtx, cancel := context.WithCancel(context.Background())
+    triggered := make(chan error, 1)
+    go func() {
+        _, err := keys.Key(triggerCtx, "key-1")
+        triggered <- err
+    }()
+    for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+        keys.mu.Lock()
+        started := keys.refreshing != nil
+        keys.mu.Unlock()
+        if started {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatal("refresh never started")
+        }
+    }
+
+    waiter := make(chan error, 1)
+    go func() {
+        _, err := keys.Key(context.Background(), "key-1")
+        waiter <- err
+    }()
+
+    // The client disconnects mid-fetch
+    cancel()
+    if err := <-triggered; err != context.Canceled {
+        t.Errorf("triggering request err = %v, want context.Canceled", err)
+    }
+
+    close(release)
+    select {
+    case err := <-waiter:
+        if err != nil {
+            t.Errorf("waiting request: %v", err)
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatal("waiting request never got the key")
+    }
+}
+
+func TestStaticKeySetUnknownKid(t *testing.T) {
+    keys := NewStaticKeySet(nil)
+    if _, err := keys.Key(context.Background(), "missing"); err != ErrKeyNotFound {
+        t.Errorf("err = %v, want ErrKeyNotFound", err)
+    }
+}
\ No newline at end of file
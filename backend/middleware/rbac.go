@@ -0,0 +1,23 @@
+package middleware
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+
+    "status-page-backend/models"
+)
+
+// RequirePermission rejects the request with 403 unless the caller's role in
+// the current organization grants perm. Must run after TenantMiddleware.
+func RequirePermission(perm models.Permission) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        role, _ := c.Get("member_role")
+        if r, ok := role.(models.Role); !ok || !r.Can(perm) {
+            c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+
+    "status-page-backend/models"
+)
+
+func TestRequirePermission(t *testing.T) {
+    tests := []struct {
+        name string
+        role interface{}
+        perm models.Permission
+        want int
+    }{
+        {"viewer reads services", models.RoleViewer, models.PermServicesRead, http.StatusOK},
+        {"viewer writes services", models.RoleViewer, models.PermServicesWrite, http.StatusForbidden},
+        {"editor writes incidents", models.RoleEditor, models.PermIncidentsWrite, http.StatusOK},
+        {"editor writes postmortems", models.RoleEditor, models.PermPostmortemsWrite, http.StatusForbidden},
+        {"editor manages members", models.RoleEditor, models.PermMembersManage, http.StatusForbidden},
+        {"admin manages members", models.RoleAdmin, models.PermMembersManage, http.StatusOK},
+        {"admin deletes organization", models.RoleAdmin, models.PermOrganizationDelete, http.StatusForbidden},
+        {"owner deletes organization", models.RoleOwner, models.PermOrganizationDelete, http.StatusOK},
+        {"no role", nil, models.PermServicesRead, http.StatusForbidden},
+        {"role as plain string", "owner", models.PermServicesRead, http.StatusForbidden},
+    }
+
+    gin.SetMode(gin.TestMode)
+    for _, tt := range tests {
+        r := gin.New()
+        r.Use(func(c *gin.Context) {
+            if tt.role != nil {
+                c.Set("member_role", tt.role)
+            }
+            c.Next()
+        })
+        reached := false
+        r.GET("/", RequirePermission(tt.perm), func(c *gin.Context) {
+            reached = true
+            c.Status(http.StatusOK)
+        })
+
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+        if w.Code != tt.want {
+            t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+        }
+        if reached != (tt.want == http.StatusOK) {
+            t.Errorf("%s: handler reached = %v", tt.name, reached)
+        }
+    }
+}
\ No newline at end of file
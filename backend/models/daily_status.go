@@ -0,0 +1,20 @@
+package models
+
+import (
+    "time"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DailyStatus is one service's status rolled up over a UTC day, used for the
+// uptime bars on public status pages
+type DailyStatus struct {
+    ID               primitive.ObjectID        `bson:"_id,omitempty" json:"id"`
+    OrganizationID   primitive.ObjectID        `bson:"organization_id" json:"organization_id"`
+    ServiceID        primitive.ObjectID        `bson:"service_id" json:"service_id"`
+    Date             time.Time                 `bson:"date" json:"date"`
+    WorstStatus      ServiceStatus             `bson:"worst_status" json:"worst_status"`
+    Minutes          map[ServiceStatus]float64 `bson:"minutes" json:"minutes"`
+    UptimePercentage float64                   `bson:"uptime_percentage" json:"uptime_percentage"`
+    IncidentIDs      []primitive.ObjectID      `bson:"incident_ids" json:"incident_ids"`
+    UpdatedAt        time.Time                 `bson:"updated_at" json:"updated_at"`
+}
\ No newline at end of file
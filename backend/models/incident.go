@@ -14,6 +14,61 @@ const (
     IncidentStatusResolved      IncidentStatus = "resolved"
 )
 
+// incidentTransitions lists where each status may move next. A resolved
+// incident can only be reopened.
+var incidentTransitions = map[IncidentStatus][]IncidentStatus{
+    IncidentStatusInvestigating: {IncidentStatusIdentified, IncidentStatusMonitoring, IncidentStatusResolved},
+    IncidentStatusIdentified:    {IncidentStatusInvestigating, IncidentStatusMonitoring, IncidentStatusResolved},
+    IncidentStatusMonitoring:    {IncidentStatusInvestigating, IncidentStatusIdentified, IncidentStatusResolved},
+    IncidentStatusResolved:      {IncidentStatusInvestigating},
+}
+
+// Valid reports whether s is one of the defined incident statuses
+func (s IncidentStatus) Valid() bool {
+    _, ok := incidentTransitions[s]
+    return ok
+}
+
+// CanMoveTo reports whether an incident in status s may move to next.
+// Staying in the same status is always allowed.
+func (s IncidentStatus) CanMoveTo(next IncidentStatus) bool {
+    if s == next {
+        return true
+    }
+    for _, allowed := range incidentTransitions[s] {
+        if allowed == next {
+            return true
+        }
+    }
+    return false
+}
+
+type IncidentImpact string
+
+const (
+    ImpactNone     IncidentImpact = "none"
+    ImpactMinor    IncidentImpact = "minor"
+    ImpactMajor    IncidentImpact = "major"
+    ImpactCritical IncidentImpact = "critical"
+)
+
+// Valid reports whether i is one of the defined impact levels
+func (i IncidentImpact) Valid() bool {
+    switch i {
+    case ImpactNone, ImpactMinor, ImpactMajor, ImpactCritical:
+        return true
+    }
+    return false
+}
+
+// ServiceImpact is the status an incident puts one of its services in.
+// PreviousStatus is what the service goes back to when the incident resolves.
+type ServiceImpact struct {
+    ServiceID      primitive.ObjectID `bson:"service_id" json:"service_id"`
+    Status         ServiceStatus      `bson:"status" json:"status"`
+    PreviousStatus ServiceStatus      `bson:"previous_status,omitempty" json:"previous_status,omitempty"`
+}
+
 type Incident struct {
     ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
     OrganizationID primitive.ObjectID   `bson:"organization_id" json:"organization_id"`
@@ -21,8 +76,89 @@ type Incident struct {
     Description    string               `bson:"description" json:"description"`
     Status         IncidentStatus       `bson:"status" json:"status"`
     Type           string               `bson:"type" json:"type"`
+    Impact         IncidentImpact       `bson:"impact,omitempty" json:"impact,omitempty"`
     AffectedServices []primitive.ObjectID `bson:"affected_services" json:"affected_services"`
+    ServiceStatuses  []ServiceImpact      `bson:"service_statuses,omitempty" json:"service_statuses,omitempty"`
     CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
     UpdatedAt      time.Time            `bson:"updated_at" json:"updated_at"`
+    ResolvedAt     *time.Time           `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
     CreatedBy      string               `bson:"created_by" json:"created_by"`
+    Updates        []IncidentUpdate     `bson:"updates,omitempty" json:"updates,omitempty"`
+    Postmortem     *Postmortem          `bson:"postmortem,omitempty" json:"postmortem,omitempty"`
+}
+
+// ClosedAt returns when the incident was resolved, or nil while it is open.
+// Incidents resolved without a resolved_at fall back to their last update.
+func (i *Incident) ClosedAt() *time.Time {
+    if i.ResolvedAt != nil {
+        return i.ResolvedAt
+    }
+    if i.Status == IncidentStatusResolved {
+        return &i.UpdatedAt
+    }
+    return nil
+}
+
+// IncidentUpdate is one entry in an incident's timeline
+type IncidentUpdate struct {
+    ID        primitive.ObjectID `bson:"_id" json:"id"`
+    Status    IncidentStatus     `bson:"status" json:"status"`
+    Message   string             `bson:"message" json:"message"`
+    CreatedBy string             `bson:"created_by" json:"created_by"`
+    CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Timeline returns the incident's updates oldest first. Incidents created
+// before updates existed get a single entry built from their description.
+func (i *Incident) Timeline() []IncidentUpdate {
+    if len(i.Updates) > 0 {
+        return i.Updates
+    }
+    return []IncidentUpdate{{
+        ID:        i.ID,
+        Status:    i.Status,
+        Message:   i.Description,
+        CreatedBy: i.CreatedBy,
+        CreatedAt: i.CreatedAt,
+    }}
+}
+
+type PostmortemStatus string
+
+const (
+    PostmortemDraft     PostmortemStatus = "draft"
+    PostmortemPublished PostmortemStatus = "published"
+)
+
+// Postmortem is the write-up of a resolved incident. Only published
+// postmortems are shown on the public status page.
+type Postmortem struct {
+    Body        string           `bson:"body" json:"body"`
+    RootCause   string           `bson:"root_cause" json:"root_cause"`
+    ActionItems []ActionItem     `bson:"action_items" json:"action_items"`
+    Status      PostmortemStatus `bson:"status" json:"status"`
+    PublishedAt *time.Time       `bson:"published_at,omitempty" json:"published_at,omitempty"`
+    CreatedBy   string           `bson:"created_by" json:"created_by"`
+    UpdatedBy   string           `bson:"updated_by" json:"updated_by"`
+    CreatedAt   time.Time        `bson:"created_at" json:"created_at"`
+    UpdatedAt   time.Time        `bson:"updated_at" json:"updated_at"`
+}
+
+// ActionItem is a follow-up task agreed in a postmortem
+type ActionItem struct {
+    ID          primitive.ObjectID `bson:"_id" json:"id"`
+    Description string             `bson:"description" json:"description"`
+    Owner       string             `bson:"owner" json:"owner"`
+    DueDate     *time.Time         `bson:"due_date,omitempty" json:"due_date,omitempty"`
+    Done        bool               `bson:"done" json:"done"`
+}
+
+// Public returns the incident as shown on the public status page, without an
+// unpublished postmortem
+func (i Incident) Public() Incident {
+    if i.Postmortem != nil && i.Postmortem.Status != PostmortemPublished {
+        i.Postmortem = nil
+    }
+    i.Updates = i.Timeline()
+    return i
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package models
+
+import (
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IncidentTemplate holds the text and defaults an incident can be opened
+// from. Title and description may contain {{variable}} placeholders.
+type IncidentTemplate struct {
+    ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+    OrganizationID   primitive.ObjectID   `bson:"organization_id" json:"organization_id"`
+    Name             string               `bson:"name" json:"name"`
+    Title            string               `bson:"title" json:"title"`
+    Description      string               `bson:"description" json:"description"`
+    Type             string               `bson:"type" json:"type"`
+    Impact           IncidentImpact       `bson:"impact,omitempty" json:"impact,omitempty"`
+    AffectedServices []primitive.ObjectID `bson:"affected_services" json:"affected_services"`
+    ServiceStatuses  []ServiceImpact      `bson:"service_statuses,omitempty" json:"service_statuses,omitempty"`
+    CreatedBy        string               `bson:"created_by" json:"created_by"`
+    Deleted          bool                 `bson:"deleted,omitempty" json:"deleted"`
+    CreatedAt        time.Time            `bson:"created_at" json:"created_at"`
+    UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// MissingVariablesError lists the placeholders RenderTemplate had no value for
+type MissingVariablesError []string
+
+func (e MissingVariablesError) Error() string {
+    return "missing template variables: " + strings.Join(e, ", ")
+}
+
+// RenderTemplate replaces {{name}} placeholders in text with their values.
+// It fails with a MissingVariablesError if any placeholder has no value.
+func RenderTemplate(text string, vars map[string]string) (string, error) {
+    missing := make(map[string]bool)
+    rendered := templateVariable.ReplaceAllStringFunc(text, func(match string) string {
+        name := templateVariable.FindStringSubmatch(match)[1]
+        value, ok := vars[name]
+        if !ok {
+            missing[name] = true
+            return match
+        }
+        return value
+    })
+
+    if len(missing) > 0 {
+        names := make([]string, 0, len(missing))
+        for name := range missing {
+            names = append(names, name)
+        }
+        sort.Strings(names)
+        return rendered, MissingVariablesError(names)
+    }
+    return rendered, nil
+}
\ No newline at end of file
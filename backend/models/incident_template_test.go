@@ -0,0 +1,54 @@
+package models
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+    vars := map[string]string{"service": "API", "region": "eu-west-1", "empty": "", "nested": "{{region}}"}
+    tests := []struct {
+        text string
+        want string
+    }{
+        {"No placeholders", "No placeholders"},
+        {"{{service}} is down", "API is down"},
+        {"{{ service }} in {{region}}, again {{service}}", "API in eu-west-1, again API"},
+        {"[{{empty}}]", "[]"},
+        // Values are inserted as is, not rendered again
+        {"{{nested}}", "{{region}}"},
+        // Only word characters form a placeholder
+        {"{{not a var}} and {service}", "{{not a var}} and {service}"},
+    }
+    for _, tt := range tests {
+        got, err := RenderTemplate(tt.text, vars)
+        if err != nil {
+            t.Errorf("RenderTemplate(%q): %v", tt.text, err)
+            continue
+        }
+        if got != tt.want {
+            t.Errorf("RenderTemplate(%q) = %q, want %q", tt.text, got, tt.want)
+        }
+    }
+}
+
+func TestRenderTemplateMissingVariables(t *testing.T) {
+    rendered, err := RenderTemplate("{{service}} down in {{zone}}, ETA {{eta}}, {{zone}}", map[string]string{"service": "API"})
+    missing, ok := err.(MissingVariablesError)
+    if !ok {
+        t.Fatalf("err = %v, want a MissingVariablesError", err)
+    }
+    if want := (MissingVariablesError{"eta", "zone"}); !reflect.DeepEqual(missing, want) {
+        t.Errorf("missing = %v, want %v sorted and without duplicates", missing, want)
+    }
+    if err.Error() != "missing template variables: eta, zone" {
+        t.Errorf("message = %q", err.Error())
+    }
+    if rendered != "API down in {{zone}}, ETA {{eta}}, {{zone}}" {
+        t.Errorf("rendered = %q, want known variables filled and the rest left", rendered)
+    }
+
+    if _, err := RenderTemplate("{{service}}", nil); err == nil {
+        t.Error("nil variables: want a MissingVariablesError")
+    }
+}
\ No newline at end of file
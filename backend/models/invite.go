@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Invite struct {
+    ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+    OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+    Email          string             `bson:"email" json:"email"`
+    Role           Role               `bson:"role" json:"role"`
+    TokenHash      string             `bson:"token_hash" json:"-"`
+    InvitedBy      string             `bson:"invited_by" json:"invited_by"`
+    ExpiresAt      time.Time          `bson:"expires_at" json:"expires_at"`
+    AcceptedAt     *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+    AcceptedBy     string             `bson:"accepted_by,omitempty" json:"accepted_by,omitempty"`
+    CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package models
+
+import (
+    "time"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LatencySample is the response time of one monitor check
+type LatencySample struct {
+    ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+    OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+    ServiceID      primitive.ObjectID `bson:"service_id" json:"service_id"`
+    LatencyMs      float64            `bson:"latency_ms" json:"latency_ms"`
+    Healthy        bool               `bson:"healthy" json:"healthy"`
+    CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
\ No newline at end of file
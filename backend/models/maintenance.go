@@ -0,0 +1,135 @@
+package models
+
+import (
+    "errors"
+    "strings"
+    "time"
+
+    "github.com/teambition/rrule-go"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MaintenanceStatus string
+
+const (
+    MaintenanceScheduled  MaintenanceStatus = "scheduled"
+    MaintenanceInProgress MaintenanceStatus = "in_progress"
+    MaintenanceCompleted  MaintenanceStatus = "completed"
+)
+
+// Maintenance is a planned window during which the affected services are put
+// into maintenance. The scheduler moves it through its statuses.
+type Maintenance struct {
+    ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+    OrganizationID   primitive.ObjectID   `bson:"organization_id" json:"organization_id"`
+    Title            string               `bson:"title" json:"title"`
+    Description      string               `bson:"description" json:"description"`
+    AffectedServices []primitive.ObjectID `bson:"affected_services" json:"affected_services"`
+    ScheduledStart   time.Time            `bson:"scheduled_start" json:"scheduled_start"`
+    ScheduledEnd     time.Time            `bson:"scheduled_end" json:"scheduled_end"`
+    Status           MaintenanceStatus    `bson:"status" json:"status"`
+    // ServiceStatuses records what each service was moved from when the
+    // window started, so it can be put back at the end
+    ServiceStatuses  []ServiceImpact      `bson:"service_statuses,omitempty" json:"service_statuses,omitempty"`
+    // Occurrences of a MaintenanceSeries remember which occurrence they are,
+    // and whether they were edited on their own since being expanded
+    SeriesID         *primitive.ObjectID  `bson:"series_id,omitempty" json:"series_id,omitempty"`
+    OccurrenceStart  *time.Time           `bson:"occurrence_start,omitempty" json:"occurrence_start,omitempty"`
+    Modified         bool                 `bson:"modified,omitempty" json:"modified,omitempty"`
+    StartedAt        *time.Time           `bson:"started_at,omitempty" json:"started_at,omitempty"`
+    CompletedAt      *time.Time           `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+    CreatedBy        string               `bson:"created_by" json:"created_by"`
+    Deleted          bool                 `bson:"deleted,omitempty" json:"deleted"`
+    CreatedAt        time.Time            `bson:"created_at" json:"created_at"`
+    UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// MaintenanceSeries is a recurring maintenance window, e.g. every second
+// Sunday 02:00-04:00. Occurrences are expanded ahead of time into Maintenance
+// documents, which can then be edited or cancelled one at a time.
+type MaintenanceSeries struct {
+    ID               primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+    OrganizationID   primitive.ObjectID   `bson:"organization_id" json:"organization_id"`
+    Title            string               `bson:"title" json:"title"`
+    Description      string               `bson:"description" json:"description"`
+    AffectedServices []primitive.ObjectID `bson:"affected_services" json:"affected_services"`
+    // RRule is an iCalendar recurrence rule without DTSTART, e.g.
+    // FREQ=WEEKLY;INTERVAL=2;BYDAY=SU
+    RRule            string               `bson:"rrule" json:"rrule"`
+    // Timezone is the IANA zone StartLocal is in, so windows keep their wall
+    // clock time across DST changes
+    Timezone         string               `bson:"timezone" json:"timezone"`
+    StartLocal       string               `bson:"start_local" json:"start_local"`
+    DurationMinutes  int                  `bson:"duration_minutes" json:"duration_minutes"`
+    CreatedBy        string               `bson:"created_by" json:"created_by"`
+    Deleted          bool                 `bson:"deleted,omitempty" json:"deleted"`
+    CreatedAt        time.Time            `bson:"created_at" json:"created_at"`
+    UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// StartLocalLayout is the format of MaintenanceSeries.StartLocal
+const StartLocalLayout = "2006-01-02T15:04"
+
+// Rule parses the series' recurrence rule anchored at its first start
+func (s *MaintenanceSeries) Rule() (*rrule.RRule, error) {
+    if s.Timezone == "" {
+        s.Timezone = "UTC"
+    }
+    loc, err := time.LoadLocation(s.Timezone)
+    if err != nil {
+        return nil, errors.New("unknown timezone: " + s.Timezone)
+    }
+
+    start, err := time.ParseInLocation(StartLocalLayout, s.StartLocal, loc)
+    if err != nil {
+        return nil, errors.New("start_local must look like 2006-01-02T15:04")
+    }
+
+    if strings.Contains(strings.ToUpper(s.RRule), "DTSTART") {
+        return nil, errors.New("rrule must not contain DTSTART, use start_local instead")
+    }
+    option, err := rrule.StrToROptionInLocation(strings.TrimPrefix(s.RRule, "RRULE:"), loc)
+    if err != nil {
+        return nil, errors.New("invalid rrule: " + err.Error())
+    }
+    switch option.Freq {
+    case rrule.DAILY, rrule.WEEKLY, rrule.MONTHLY, rrule.YEARLY:
+    default:
+        return nil, errors.New("rrule FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+    }
+    option.Dtstart = start
+
+    return rrule.NewRRule(*option)
+}
+
+// Validate checks the series can be expanded
+func (s *MaintenanceSeries) Validate() error {
+    if s.Title == "" {
+        return errors.New("title is required")
+    }
+    if s.DurationMinutes < 1 || s.DurationMinutes > 7*24*60 {
+        return errors.New("duration_minutes must be between 1 and 10080")
+    }
+    _, err := s.Rule()
+    return err
+}
+
+func (s *MaintenanceSeries) Duration() time.Duration {
+    return time.Duration(s.DurationMinutes) * time.Minute
+}
+
+// Occurrences returns the start of every window that overlaps [from, to)
+func (s *MaintenanceSeries) Occurrences(from, to time.Time) ([]time.Time, error) {
+    rule, err := s.Rule()
+    if err != nil {
+        return nil, err
+    }
+    starts := rule.Between(from.Add(-s.Duration()), to, false)
+    occurrences := make([]time.Time, 0, len(starts))
+    for _, start := range starts {
+        if start.Add(s.Duration()).After(from) {
+            occurrences = append(occurrences, start.UTC())
+        }
+    }
+    return occurrences, nil
+}
\ No newline at end of file
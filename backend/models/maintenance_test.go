@@ -0,0 +1,97 @@
+package models
+
+import (
+    "testing"
+    "time"
+)
+
+func TestMaintenanceSeriesOccurrences(t *testing.T) {
+    utc := func(value string) time.Time {
+        parsed, err := time.Parse(time.RFC3339, value)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return parsed
+    }
+
+    tests := []struct {
+        name   string
+        series MaintenanceSeries
+        from   string
+        to     string
+        want   []string
+    }{
+        {
+            // Berlin moves to CEST on 2024-03-31, the window stays at 04:00 local
+            name:   "weekly across DST",
+            series: MaintenanceSeries{RRule: "FREQ=WEEKLY;BYDAY=SU", Timezone: "Europe/Berlin", StartLocal: "2024-03-17T04:00", DurationMinutes: 120},
+            from:   "2024-03-17T00:00:00Z",
+            to:     "2024-04-08T00:00:00Z",
+            want:   []string{"2024-03-17T03:00:00Z", "2024-03-24T03:00:00Z", "2024-03-31T02:00:00Z", "2024-04-07T02:00:00Z"},
+        },
+        {
+            name:   "every second Sunday",
+            series: MaintenanceSeries{RRule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", Timezone: "UTC", StartLocal: "2024-01-07T02:00", DurationMinutes: 60},
+            from:   "2024-01-01T00:00:00Z",
+            to:     "2024-02-01T00:00:00Z",
+            want:   []string{"2024-01-07T02:00:00Z", "2024-01-21T02:00:00Z"},
+        },
+        {
+            name:   "window in progress at from",
+            series: MaintenanceSeries{RRule: "FREQ=DAILY;COUNT=3", StartLocal: "2024-01-01T23:00", DurationMinutes: 120},
+            from:   "2024-01-02T00:30:00Z",
+            to:     "2024-01-03T00:00:00Z",
+            want:   []string{"2024-01-01T23:00:00Z", "2024-01-02T23:00:00Z"},
+        },
+        {
+            name:   "window ended at from",
+            series: MaintenanceSeries{RRule: "FREQ=DAILY;COUNT=2", StartLocal: "2024-01-01T22:00", DurationMinutes: 120},
+            from:   "2024-01-02T00:00:00Z",
+            to:     "2024-01-02T12:00:00Z",
+            want:   []string{},
+        },
+    }
+    for _, tt := range tests {
+        got, err := tt.series.Occurrences(utc(tt.from), utc(tt.to))
+        if err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+            continue
+        }
+        if len(got) != len(tt.want) {
+            t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+            continue
+        }
+        for i := range got {
+            if !got[i].Equal(utc(tt.want[i])) || got[i].Location() != time.UTC {
+                t.Errorf("%s: occurrence %d = %v, want %s", tt.name, i, got[i], tt.want[i])
+            }
+        }
+    }
+}
+
+func TestMaintenanceSeriesValidate(t *testing.T) {
+    valid := MaintenanceSeries{Title: "Patching", RRule: "FREQ=WEEKLY;BYDAY=SU", Timezone: "Europe/Berlin", StartLocal: "2024-03-17T04:00", DurationMinutes: 120}
+    if err := valid.Validate(); err != nil {
+        t.Fatalf("valid series: %v", err)
+    }
+
+    tests := []struct {
+        name   string
+        change func(*MaintenanceSeries)
+    }{
+        {"no title", func(s *MaintenanceSeries) { s.Title = "" }},
+        {"no duration", func(s *MaintenanceSeries) { s.DurationMinutes = 0 }},
+        {"unknown timezone", func(s *MaintenanceSeries) { s.Timezone = "Mars/Olympus" }},
+        {"bad start", func(s *MaintenanceSeries) { s.StartLocal = "2024-03-17 04:00" }},
+        {"DTSTART in rule", func(s *MaintenanceSeries) { s.RRule = "DTSTART:20240317T040000\nRRULE:FREQ=WEEKLY" }},
+        {"hourly", func(s *MaintenanceSeries) { s.RRule = "FREQ=HOURLY" }},
+        {"garbage", func(s *MaintenanceSeries) { s.RRule = "FREQ=SOMETIMES" }},
+    }
+    for _, tt := range tests {
+        series := valid
+        tt.change(&series)
+        if err := series.Validate(); err == nil {
+            t.Errorf("%s: Validate() = nil, want an error", tt.name)
+        }
+    }
+}
\ No newline at end of file
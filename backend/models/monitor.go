@@ -0,0 +1,290 @@
+package models
+
+import (
+    "errors"
+    "net"
+    "net/http"
+    "net/url"
+    "strings"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MonitorType string
+
+const (
+    MonitorHTTP MonitorType = "http"
+    MonitorTCP  MonitorType = "tcp"
+    MonitorDNS  MonitorType = "dns"
+    MonitorTLS  MonitorType = "tls"
+
+    // MonitorHeartbeat is a push monitor, the service pings us instead
+    MonitorHeartbeat MonitorType = "heartbeat"
+)
+
+// MonitorConfig describes the automated check run against a service
+type MonitorConfig struct {
+    Enabled             bool          `bson:"enabled" json:"enabled"`
+    Type                MonitorType   `bson:"type" json:"type"`
+    Method              string        `bson:"method,omitempty" json:"method,omitempty"`
+    IntervalSeconds     int           `bson:"interval_seconds" json:"interval_seconds"`
+    TimeoutSeconds      int           `bson:"timeout_seconds" json:"timeout_seconds"`
+    ExpectedStatusCodes []int         `bson:"expected_status_codes,omitempty" json:"expected_status_codes,omitempty"`
+    BodyContains        string        `bson:"body_contains,omitempty" json:"body_contains,omitempty"`
+    FailureThreshold    int           `bson:"failure_threshold" json:"failure_threshold"`
+    SuccessThreshold    int           `bson:"success_threshold" json:"success_threshold"`
+    FailureStatus       ServiceStatus `bson:"failure_status" json:"failure_status"`
+
+    // Target overrides the host derived from the service URL: host:port for
+    // tcp and tls, a hostname for dns
+    Target string `bson:"target,omitempty" json:"target,omitempty"`
+
+    // DNS checks
+    RecordType      string   `bson:"record_type,omitempty" json:"record_type,omitempty"`
+    ExpectedRecords []string `bson:"expected_records,omitempty" json:"expected_records,omitempty"`
+    Nameserver      string   `bson:"nameserver,omitempty" json:"nameserver,omitempty"`
+
+    // TLS checks warn (degraded_performance) when the certificate expires
+    // within this many days
+    CertExpiryWarningDays int `bson:"cert_expiry_warning_days,omitempty" json:"cert_expiry_warning_days,omitempty"`
+
+    // Latency above LatencyThresholdMs for LatencyWindowSeconds marks the
+    // service degraded_performance, zero disables the rule
+    LatencyThresholdMs   int `bson:"latency_threshold_ms,omitempty" json:"latency_threshold_ms,omitempty"`
+    LatencyWindowSeconds int `bson:"latency_window_seconds,omitempty" json:"latency_window_seconds,omitempty"`
+
+    // Heartbeat monitors expect a ping every PeriodSeconds and fail once
+    // PeriodSeconds + GraceSeconds pass without one
+    PeriodSeconds int `bson:"period_seconds,omitempty" json:"period_seconds,omitempty"`
+    GraceSeconds  int `bson:"grace_seconds,omitempty" json:"grace_seconds,omitempty"`
+
+    // AutoIncident opens an incident when the monitor takes the service down
+    // and resolves it once checks have passed for AutoResolveMinutes
+    AutoIncident       bool `bson:"auto_incident,omitempty" json:"auto_incident,omitempty"`
+    AutoResolveMinutes int  `bson:"auto_resolve_minutes,omitempty" json:"auto_resolve_minutes,omitempty"`
+}
+
+// MonitorState is what the monitor remembers between checks
+type MonitorState struct {
+    LastCheckedAt        time.Time     `bson:"last_checked_at" json:"last_checked_at"`
+    LastMessage          string        `bson:"last_message" json:"last_message"`
+    Healthy              bool          `bson:"healthy" json:"healthy"`
+    ConsecutiveFailures  int           `bson:"consecutive_failures" json:"consecutive_failures"`
+    ConsecutiveSuccesses int           `bson:"consecutive_successes" json:"consecutive_successes"`
+    // AppliedStatus is set while the monitor has moved the service out of
+    // operational, so it only ever reverts its own changes
+    AppliedStatus        ServiceStatus `bson:"applied_status,omitempty" json:"applied_status,omitempty"`
+    // SlowSince is when latency first went over the threshold in the current run
+    SlowSince            *time.Time    `bson:"slow_since,omitempty" json:"slow_since,omitempty"`
+    // IncidentID is the incident the monitor opened and still manages
+    IncidentID           *primitive.ObjectID `bson:"incident_id,omitempty" json:"incident_id,omitempty"`
+    IncidentUpdatedAt    *time.Time    `bson:"incident_updated_at,omitempty" json:"incident_updated_at,omitempty"`
+    RecoveredAt          *time.Time    `bson:"recovered_at,omitempty" json:"recovered_at,omitempty"`
+}
+
+const (
+    MinMonitorInterval = 10
+    MaxMonitorTimeout  = 60
+)
+
+// ApplyDefaults fills in unset fields
+func (m *MonitorConfig) ApplyDefaults() {
+    if m.Type == "" {
+        m.Type = MonitorHTTP
+    }
+    if m.Type == MonitorHTTP {
+        m.Method = strings.ToUpper(m.Method)
+        if m.Method == "" {
+            m.Method = http.MethodGet
+        }
+    }
+    if m.Type == MonitorDNS && m.RecordType == "" {
+        m.RecordType = "A"
+    }
+    if m.Type == MonitorTLS && m.CertExpiryWarningDays == 0 {
+        m.CertExpiryWarningDays = 14
+    }
+    if m.Type == MonitorHeartbeat {
+        if m.GraceSeconds == 0 {
+            m.GraceSeconds = 60
+        }
+        // Only compares timestamps, so it can be evaluated often
+        if m.IntervalSeconds == 0 {
+            m.IntervalSeconds = MinMonitorInterval
+        }
+    }
+    if m.IntervalSeconds == 0 {
+        m.IntervalSeconds = 60
+    }
+    if m.TimeoutSeconds == 0 {
+        m.TimeoutSeconds = 10
+    }
+    if m.FailureThreshold == 0 {
+        m.FailureThreshold = 3
+    }
+    if m.SuccessThreshold == 0 {
+        m.SuccessThreshold = 2
+    }
+    if m.FailureStatus == "" {
+        m.FailureStatus = StatusMajorOutage
+    }
+    if m.LatencyThresholdMs > 0 && m.LatencyWindowSeconds == 0 {
+        m.LatencyWindowSeconds = 300
+    }
+    if m.AutoIncident && m.AutoResolveMinutes == 0 {
+        m.AutoResolveMinutes = 15
+    }
+}
+
+// Validate checks a config after defaults have been applied
+func (m *MonitorConfig) Validate() error {
+    switch m.Type {
+    case MonitorHTTP:
+        if !httpMethods[m.Method] {
+            return errors.New("method must be one of GET, HEAD, POST or OPTIONS")
+        }
+    case MonitorTCP, MonitorTLS:
+    case MonitorHeartbeat:
+        if m.PeriodSeconds < MinMonitorInterval {
+            return errors.New("period_seconds must be at least 10")
+        }
+        if m.GraceSeconds < 0 {
+            return errors.New("grace_seconds cannot be negative")
+        }
+    case MonitorDNS:
+        if !dnsRecordTypes[m.RecordType] {
+            return errors.New("record_type must be one of A, AAAA, CNAME, MX, NS or TXT")
+        }
+        if m.Nameserver != "" {
+            if _, _, err := net.SplitHostPort(m.Nameserver); err != nil {
+                return errors.New("nameserver must be host:port")
+            }
+        }
+    default:
+        return errors.New("unknown monitor type: " + string(m.Type))
+    }
+
+    if m.IntervalSeconds < MinMonitorInterval {
+        return errors.New("interval_seconds must be at least 10")
+    }
+    if m.TimeoutSeconds < 1 || m.TimeoutSeconds > MaxMonitorTimeout || m.TimeoutSeconds > m.IntervalSeconds {
+        return errors.New("timeout_seconds must be between 1 and 60 and not exceed the interval")
+    }
+    if m.FailureThreshold < 1 || m.SuccessThreshold < 1 {
+        return errors.New("thresholds must be at least 1")
+    }
+    if !m.FailureStatus.Valid() || m.FailureStatus == StatusOperational || m.FailureStatus == StatusMaintenance {
+        return errors.New("failure_status must be degraded_performance, partial_outage or major_outage")
+    }
+    for _, code := range m.ExpectedStatusCodes {
+        if code < 100 || code > 599 {
+            return errors.New("expected_status_codes must be valid HTTP status codes")
+        }
+    }
+    if m.LatencyThresholdMs < 0 || m.LatencyWindowSeconds < 0 {
+        return errors.New("latency settings cannot be negative")
+    }
+    if m.CertExpiryWarningDays < 0 || m.CertExpiryWarningDays > 365 {
+        return errors.New("cert_expiry_warning_days must be between 0 and 365")
+    }
+    if m.AutoResolveMinutes < 0 || m.AutoResolveMinutes > 24*60 {
+        return errors.New("auto_resolve_minutes must be between 0 and 1440")
+    }
+    return nil
+}
+
+// httpMethods are the methods an HTTP monitor may use
+var httpMethods = map[string]bool{
+    http.MethodGet:     true,
+    http.MethodHead:    true,
+    http.MethodPost:    true,
+    http.MethodOptions: true,
+}
+
+var dnsRecordTypes = map[string]bool{
+    "A":     true,
+    "AAAA":  true,
+    "CNAME": true,
+    "MX":    true,
+    "NS":    true,
+    "TXT":   true,
+}
+
+// TargetFor returns what the monitor probes: the URL for http, host:port for
+// tcp and tls, or a hostname for dns. Unless Target is set it is derived from
+// the service URL. Heartbeat monitors have no target.
+func (m *MonitorConfig) TargetFor(serviceURL string) (string, error) {
+    if m.Type == MonitorHeartbeat {
+        return "", nil
+    }
+    if m.Type == MonitorHTTP {
+        u, err := url.Parse(serviceURL)
+        if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            return "", errors.New("HTTP monitors need a service URL starting with http:// or https://")
+        }
+        return serviceURL, nil
+    }
+
+    if m.Target != "" {
+        if m.Type == MonitorDNS {
+            return m.Target, nil
+        }
+        if m.Type == MonitorTLS {
+            if _, _, err := net.SplitHostPort(m.Target); err != nil {
+                return net.JoinHostPort(m.Target, "443"), nil
+            }
+            return m.Target, nil
+        }
+        if _, _, err := net.SplitHostPort(m.Target); err != nil {
+            return "", errors.New("target must be host:port")
+        }
+        return m.Target, nil
+    }
+
+    u, err := url.Parse(serviceURL)
+    if err != nil || u.Hostname() == "" {
+        return "", errors.New("set a target or a service URL to derive it from")
+    }
+    if m.Type == MonitorDNS {
+        return u.Hostname(), nil
+    }
+
+    port := u.Port()
+    if port == "" {
+        switch {
+        case m.Type == MonitorTLS || u.Scheme == "https":
+            port = "443"
+        case u.Scheme == "http":
+            port = "80"
+        default:
+            return "", errors.New("target must include a port")
+        }
+    }
+    return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+func (m *MonitorConfig) Interval() time.Duration {
+    return time.Duration(m.IntervalSeconds) * time.Second
+}
+
+func (m *MonitorConfig) Timeout() time.Duration {
+    return time.Duration(m.TimeoutSeconds) * time.Second
+}
+
+func (m *MonitorConfig) LatencyThreshold() time.Duration {
+    return time.Duration(m.LatencyThresholdMs) * time.Millisecond
+}
+
+func (m *MonitorConfig) LatencyWindow() time.Duration {
+    return time.Duration(m.LatencyWindowSeconds) * time.Second
+}
+
+func (m *MonitorConfig) AutoResolveAfter() time.Duration {
+    return time.Duration(m.AutoResolveMinutes) * time.Minute
+}
+
+// HeartbeatDeadline is how long after a ping the next one must arrive
+func (m *MonitorConfig) HeartbeatDeadline() time.Duration {
+    return time.Duration(m.PeriodSeconds+m.GraceSeconds) * time.Second
+}
\ No newline at end of file
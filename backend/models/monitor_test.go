@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestMonitorConfigMethod(t *testing.T) {
+    tests := []struct {
+        method string
+        want   string
+        valid  bool
+    }{
+        {"", "GET", true},
+        {"head", "HEAD", true},
+        {"POST", "POST", true},
+        {"OPTIONS", "OPTIONS", true},
+        {"DELETE", "DELETE", false},
+        {"CONNECT", "CONNECT", false},
+        {"GET /admin", "GET /ADMIN", false},
+    }
+    for _, tt := range tests {
+        cfg := MonitorConfig{Type: MonitorHTTP, Method: tt.method}
+        cfg.ApplyDefaults()
+        if cfg.Method != tt.want {
+            t.Errorf("method %q became %q, want %q", tt.method, cfg.Method, tt.want)
+        }
+        if err := cfg.Validate(); (err == nil) != tt.valid {
+            t.Errorf("method %q: Validate() = %v, want valid %v", tt.method, err, tt.valid)
+        }
+    }
+}
\ No newline at end of file
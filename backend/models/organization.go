@@ -9,14 +9,45 @@ type Organization struct {
     ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
     Name        string            `bson:"name" json:"name"`
     Slug        string            `bson:"slug" json:"slug"`
+    PreviousSlugs []string        `bson:"previous_slugs,omitempty" json:"previous_slugs,omitempty"`
     Description string            `bson:"description" json:"description"`
     CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
     UpdatedAt   time.Time         `bson:"updated_at" json:"updated_at"`
     Members     []Member          `bson:"members" json:"members"`
+    Deleted     bool              `bson:"deleted,omitempty" json:"deleted"`
+}
+
+// PublicOrganization is what the public status page shows of an organization,
+// leaving out its members and slug history
+type PublicOrganization struct {
+    ID          primitive.ObjectID `json:"id"`
+    Name        string             `json:"name"`
+    Slug        string             `json:"slug"`
+    Description string             `json:"description"`
+}
+
+// Public returns the organization as shown on the public status page
+func (o Organization) Public() PublicOrganization {
+    return PublicOrganization{
+        ID:          o.ID,
+        Name:        o.Name,
+        Slug:        o.Slug,
+        Description: o.Description,
+    }
 }
 
 type Member struct {
     UserID string `bson:"user_id" json:"user_id"`
-    Role   string `bson:"role" json:"role"`
+    Role   Role   `bson:"role" json:"role"`
     Email  string `bson:"email" json:"email"`
+}
+
+// FindMember returns the membership entry for the given user, if any
+func (o *Organization) FindMember(userID string) (Member, bool) {
+    for _, m := range o.Members {
+        if m.UserID == userID {
+            return m, true
+        }
+    }
+    return Member{}, false
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+package models
+
+type Role string
+
+const (
+    RoleOwner  Role = "owner"
+    RoleAdmin  Role = "admin"
+    RoleEditor Role = "editor"
+    RoleViewer Role = "viewer"
+)
+
+type Permission string
+
+const (
+    PermServicesRead       Permission = "services:read"
+    PermServicesWrite      Permission = "services:write"
+    PermIncidentsRead      Permission = "incidents:read"
+    PermIncidentsWrite     Permission = "incidents:write"
+    PermOrganizationWrite  Permission = "organization:write"
+    PermOrganizationDelete Permission = "organization:delete"
+    PermMembersManage      Permission = "members:manage"
+    PermPostmortemsWrite   Permission = "postmortems:write"
+)
+
+var readPermissions = []Permission{
+    PermServicesRead,
+    PermIncidentsRead,
+}
+
+var writePermissions = append([]Permission{
+    PermServicesWrite,
+    PermIncidentsWrite,
+}, readPermissions...)
+
+var adminPermissions = append([]Permission{
+    PermOrganizationWrite,
+    PermMembersManage,
+    PermPostmortemsWrite,
+}, writePermissions...)
+
+var ownerPermissions = append([]Permission{
+    PermOrganizationDelete,
+}, adminPermissions...)
+
+// rolePermissions is the permission matrix, each role includes everything
+// granted to the roles below it
+var rolePermissions = map[Role][]Permission{
+    RoleOwner:  ownerPermissions,
+    RoleAdmin:  adminPermissions,
+    RoleEditor: writePermissions,
+    RoleViewer: readPermissions,
+}
+
+// Valid reports whether r is one of the defined roles
+func (r Role) Valid() bool {
+    _, ok := rolePermissions[r]
+    return ok
+}
+
+// Can reports whether the role grants the given permission
+func (r Role) Can(p Permission) bool {
+    for _, granted := range rolePermissions[r] {
+        if granted == p {
+            return true
+        }
+    }
+    return false
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestRoleCan(t *testing.T) {
+    all := []Permission{
+        PermServicesRead, PermServicesWrite, PermIncidentsRead, PermIncidentsWrite,
+        PermPostmortemsWrite, PermMembersManage, PermOrganizationWrite, PermOrganizationDelete,
+    }
+    granted := map[Role][]Permission{
+        RoleViewer: {PermServicesRead, PermIncidentsRead},
+        RoleEditor: {PermServicesRead, PermIncidentsRead, PermServicesWrite, PermIncidentsWrite},
+        RoleAdmin: {PermServicesRead, PermIncidentsRead, PermServicesWrite, PermIncidentsWrite,
+            PermPostmortemsWrite, PermMembersManage, PermOrganizationWrite},
+        RoleOwner: all,
+        // Unknown roles get nothing
+        Role("superuser"): nil,
+        Role(""):          nil,
+    }
+
+    for role, perms := range granted {
+        want := make(map[Permission]bool, len(perms))
+        for _, p := range perms {
+            want[p] = true
+        }
+        for _, p := range all {
+            if got := role.Can(p); got != want[p] {
+                t.Errorf("%q.Can(%s) = %v, want %v", role, p, got, want[p])
+            }
+        }
+    }
+}
+
+func TestRoleValid(t *testing.T) {
+    for _, role := range []Role{RoleOwner, RoleAdmin, RoleEditor, RoleViewer} {
+        if !role.Valid() {
+            t.Errorf("%s is not valid", role)
+        }
+    }
+    for _, role := range []Role{"", "Owner", "superuser"} {
+        if role.Valid() {
+            t.Errorf("%q is valid", role)
+        }
+    }
+}
\ No newline at end of file
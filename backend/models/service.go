@@ -22,7 +22,58 @@ type Service struct {
     Description    string             `bson:"description" json:"description"`
     Status         ServiceStatus      `bson:"status" json:"status"`
     URL            string             `bson:"url" json:"url"`
+    Position       int                `bson:"position" json:"position"`
+    GroupID        *primitive.ObjectID `bson:"group_id,omitempty" json:"group_id,omitempty"`
+    Monitor        *MonitorConfig     `bson:"monitor,omitempty" json:"monitor,omitempty"`
+    MonitorState   *MonitorState      `bson:"monitor_state,omitempty" json:"monitor_state,omitempty"`
+    HeartbeatTokenHash string         `bson:"heartbeat_token_hash,omitempty" json:"-"`
+    LastHeartbeatAt    *time.Time     `bson:"last_heartbeat_at,omitempty" json:"last_heartbeat_at,omitempty"`
+    // HeartbeatExpectedSince is when a token was issued or the heartbeat
+    // monitor enabled, the deadline runs from then until the first ping
+    HeartbeatExpectedSince *time.Time `bson:"heartbeat_expected_since,omitempty" json:"-"`
     Deleted   bool      `bson:"deleted,omitempty" json:"deleted"`
     CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
     UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Public returns the service as shown on the public status page, without
+// its monitor configuration and state
+func (s Service) Public() Service {
+    s.Monitor = nil
+    s.MonitorState = nil
+    s.LastHeartbeatAt = nil
+    return s
+}
+
+// severity ranks statuses from best to worst. Maintenance is planned, so it
+// ranks below any unplanned degradation.
+var severity = map[ServiceStatus]int{
+    StatusOperational:   0,
+    StatusMaintenance:   1,
+    StatusDegradedPerf:  2,
+    StatusPartialOutage: 3,
+    StatusMajorOutage:   4,
+}
+
+// Valid reports whether s is one of the defined statuses
+func (s ServiceStatus) Valid() bool {
+    _, ok := severity[s]
+    return ok
+}
+
+// Worse reports whether s is more severe than other
+func (s ServiceStatus) Worse(other ServiceStatus) bool {
+    return severity[s] > severity[other]
+}
+
+// WorstStatus returns the most severe of the given statuses, or operational
+// if there are none
+func WorstStatus(statuses ...ServiceStatus) ServiceStatus {
+    worst := StatusOperational
+    for _, s := range statuses {
+        if s.Worse(worst) {
+            worst = s
+        }
+    }
+    return worst
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServiceGroup bundles related services (e.g. "API" or "EU region") on the
+// status page
+type ServiceGroup struct {
+    ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+    OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+    Name           string             `bson:"name" json:"name"`
+    Description    string             `bson:"description" json:"description"`
+    Position       int                `bson:"position" json:"position"`
+    Deleted        bool               `bson:"deleted,omitempty" json:"deleted"`
+    CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+    UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
\ No newline at end of file
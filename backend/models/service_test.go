@@ -0,0 +1,28 @@
+package models
+
+import (
+    "testing"
+    "time"
+)
+
+func TestServicePublicHidesMonitor(t *testing.T) {
+    now := time.Now()
+    service := Service{
+        Name:            "API",
+        Status:          StatusOperational,
+        Monitor:         &MonitorConfig{Type: MonitorHTTP, Target: "10.0.0.5:8080"},
+        MonitorState:    &MonitorState{LastMessage: "dial tcp 10.0.0.5:8080: connection refused"},
+        LastHeartbeatAt: &now,
+    }
+
+    public := service.Public()
+    if public.Monitor != nil || public.MonitorState != nil || public.LastHeartbeatAt != nil {
+        t.Errorf("monitor details left on the public service: %+v", public)
+    }
+    if public.Name != "API" || public.Status != StatusOperational {
+        t.Errorf("public service lost its name or status: %+v", public)
+    }
+    if service.Monitor == nil {
+        t.Errorf("Public modified the original service")
+    }
+}
\ No newline at end of file
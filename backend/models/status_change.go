@@ -0,0 +1,24 @@
+package models
+
+import (
+    "time"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actors recorded for changes that weren't made by a user
+const (
+    ActorMonitor = "monitor"
+    ActorSystem  = "system"
+)
+
+// StatusChange records one transition of a service's status
+type StatusChange struct {
+    ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+    OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+    ServiceID      primitive.ObjectID `bson:"service_id" json:"service_id"`
+    OldStatus      ServiceStatus      `bson:"old_status" json:"old_status"`
+    NewStatus      ServiceStatus      `bson:"new_status" json:"new_status"`
+    Message        string             `bson:"message" json:"message"`
+    Actor          string             `bson:"actor" json:"actor"`
+    CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+    "context"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// Result is the outcome of a single check
+type Result struct {
+    Healthy bool
+    // Status to apply when unhealthy, empty means the config's FailureStatus
+    Status  models.ServiceStatus
+    Message string
+    Latency time.Duration
+    // Sustained failures have already lasted long enough, so the failure
+    // threshold doesn't apply
+    Sustained bool
+}
+
+// Checker probes a service once. The context carries the check timeout.
+type Checker interface {
+    Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result
+}
+
+// DefaultCheckers returns a checker for every monitor type, connecting only
+// to public addresses and verifying certificates against the system roots
+func DefaultCheckers() map[models.MonitorType]Checker {
+    return map[models.MonitorType]Checker{
+        models.MonitorHTTP: HTTPChecker{},
+        models.MonitorTCP:  TCPChecker{},
+        models.MonitorDNS:  DNSChecker{},
+        models.MonitorTLS:  TLSChecker{},
+
+        models.MonitorHeartbeat: HeartbeatChecker{},
+    }
+}
+
+func healthy(message string, latency time.Duration) Result {
+    return Result{Healthy: true, Message: message, Latency: latency}
+}
+
+func failed(message string, latency time.Duration) Result {
+    return Result{Message: message, Latency: latency}
+}
\ No newline at end of file
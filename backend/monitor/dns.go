@@ -0,0 +1,121 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "strconv"
+    "strings"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// DNSChecker resolves a hostname and, if ExpectedRecords is set, checks that
+// every expected record is among the answers
+type DNSChecker struct {
+    // Dialer connects to a custom nameserver, nil means one that only reaches
+    // public addresses
+    Dialer *net.Dialer
+}
+
+func (d DNSChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    host, err := cfg.TargetFor(service.URL)
+    if err != nil {
+        return failed(err.Error(), 0)
+    }
+
+    resolver := net.DefaultResolver
+    if cfg.Nameserver != "" {
+        dialer := d.Dialer
+        if dialer == nil {
+            dialer = publicDialer
+        }
+        resolver = &net.Resolver{
+            PreferGo: true,
+            Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+                return dialer.DialContext(ctx, network, cfg.Nameserver)
+            },
+        }
+    }
+
+    start := time.Now()
+    records, err := lookup(ctx, resolver, cfg.RecordType, host)
+    latency := time.Since(start)
+    if err != nil {
+        return failed(fmt.Sprintf("%s lookup for %s failed: %v", cfg.RecordType, host, err), latency)
+    }
+    if len(records) == 0 {
+        return failed(fmt.Sprintf("no %s records for %s", cfg.RecordType, host), latency)
+    }
+
+    found := make(map[string]bool, len(records))
+    for _, r := range records {
+        found[normalizeRecord(r)] = true
+    }
+    for _, expected := range cfg.ExpectedRecords {
+        if !found[normalizeRecord(expected)] {
+            return failed(fmt.Sprintf("%s record %q missing for %s, got %s", cfg.RecordType, expected, host, strings.Join(records, ", ")), latency)
+        }
+    }
+
+    return healthy(fmt.Sprintf("resolved %s to %s in %dms", host, strings.Join(records, ", "), latency.Milliseconds()), latency)
+}
+
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+    switch recordType {
+    case "A", "AAAA":
+        network := "ip4"
+        if recordType == "AAAA" {
+            network = "ip6"
+        }
+        ips, err := resolver.LookupIP(ctx, network, host)
+        if err != nil {
+            return nil, err
+        }
+        records := make([]string, len(ips))
+        for i, ip := range ips {
+            records[i] = ip.String()
+        }
+        return records, nil
+
+    case "CNAME":
+        cname, err := resolver.LookupCNAME(ctx, host)
+        if err != nil {
+            return nil, err
+        }
+        return []string{cname}, nil
+
+    case "MX":
+        mxs, err := resolver.LookupMX(ctx, host)
+        if err != nil {
+            return nil, err
+        }
+        records := make([]string, len(mxs))
+        for i, mx := range mxs {
+            records[i] = strconv.Itoa(int(mx.Pref)) + " " + mx.Host
+        }
+        return records, nil
+
+    case "NS":
+        nss, err := resolver.LookupNS(ctx, host)
+        if err != nil {
+            return nil, err
+        }
+        records := make([]string, len(nss))
+        for i, ns := range nss {
+            records[i] = ns.Host
+        }
+        return records, nil
+
+    case "TXT":
+        return resolver.LookupTXT(ctx, host)
+    }
+
+    return nil, fmt.Errorf("unsupported record type %q", recordType)
+}
+
+// normalizeRecord makes "Mail.Example.com." and "mail.example.com" compare equal
+func normalizeRecord(r string) string {
+    return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(r)), ".")
+}
\ No newline at end of file
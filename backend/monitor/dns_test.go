@@ -0,0 +1,127 @@
+package monitor
+
+import (
+    "context"
+    "encoding/binary"
+    "net"
+    "strings"
+    "testing"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// dnsServer answers A queries from records over UDP on loopback and
+// returns its address. Unknown names get an empty answer.
+func dnsServer(t *testing.T, records map[string][]string) string {
+    t.Helper()
+    conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+
+    go func() {
+        buf := make([]byte, 512)
+        for {
+            n, addr, err := conn.ReadFrom(buf)
+            if err != nil {
+                return
+            }
+            if response := dnsResponse(buf[:n], records); response != nil {
+                conn.WriteTo(response, addr)
+            }
+        }
+    }()
+    return conn.LocalAddr().String()
+}
+
+func dnsResponse(query []byte, records map[string][]string) []byte {
+    if len(query) < 12 {
+        return nil
+    }
+
+    // Question name, as labels
+    var labels []string
+    i := 12
+    for i < len(query) && query[i] != 0 {
+        size := int(query[i])
+        if i+1+size > len(query) {
+            return nil
+        }
+        labels = append(labels, string(query[i+1:i+1+size]))
+        i += 1 + size
+    }
+    if i+5 > len(query) {
+        return nil
+    }
+    questionEnd := i + 5
+    qtype := binary.BigEndian.Uint16(query[i+1:])
+
+    var answers []net.IP
+    if qtype == 1 {
+        for _, record := range records[strings.ToLower(strings.Join(labels, "."))] {
+            answers = append(answers, net.ParseIP(record).To4())
+        }
+    }
+
+    response := make([]byte, 0, 512)
+    response = append(response, query[0], query[1], 0x81, 0x80, 0, 1)
+    response = binary.BigEndian.AppendUint16(response, uint16(len(answers)))
+    response = append(response, 0, 0, 0, 0)
+    response = append(response, query[12:questionEnd]...)
+    for _, ip := range answers {
+        // Name points back at the question, type A, class IN, TTL 60
+        response = append(response, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+        response = append(response, ip...)
+    }
+    return response
+}
+
+func TestDNSChecker(t *testing.T) {
+    nameserver := dnsServer(t, map[string][]string{
+        "api.example.com": {"203.0.113.10", "203.0.113.11"},
+    })
+
+    tests := []struct {
+        name     string
+        host     string
+        expected []string
+        healthy  bool
+    }{
+        {"resolves", "api.example.com", nil, true},
+        {"expected records present", "api.example.com", []string{"203.0.113.11"}, true},
+        {"expected record missing", "api.example.com", []string{"203.0.113.99"}, false},
+        {"no records", "missing.example.com", nil, false},
+    }
+    for _, tt := range tests {
+        cfg := models.MonitorConfig{
+            Type:            models.MonitorDNS,
+            Target:          tt.host,
+            Nameserver:      nameserver,
+            ExpectedRecords: tt.expected,
+        }
+        cfg.ApplyDefaults()
+        result := DNSChecker{Dialer: localDialer}.Check(context.Background(), models.Service{}, cfg)
+        if result.Healthy != tt.healthy {
+            t.Errorf("%s: result = %+v, want healthy %v", tt.name, result, tt.healthy)
+        }
+    }
+}
+
+func TestDNSCheckerRefusesInternalNameservers(t *testing.T) {
+    nameserver := dnsServer(t, map[string][]string{
+        "api.example.com": {"203.0.113.10"},
+    })
+
+    for _, server := range []string{nameserver, "169.254.169.254:53", "10.0.0.2:53"} {
+        cfg := models.MonitorConfig{Type: models.MonitorDNS, Target: "api.example.com", Nameserver: server}
+        cfg.ApplyDefaults()
+        ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+        result := DNSChecker{}.Check(ctx, models.Service{}, cfg)
+        cancel()
+        if result.Healthy || !strings.Contains(result.Message, "not allowed") {
+            t.Errorf("nameserver %s: result = %+v, want the address refused", server, result)
+        }
+    }
+}
\ No newline at end of file
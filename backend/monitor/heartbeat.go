@@ -0,0 +1,45 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// HeartbeatChecker fails once a push monitor's deadline passes without a
+// ping. The deadline runs from the last ping, or from when pings were first
+// expected, whichever is later. Recovery happens in the heartbeat handler as
+// soon as a ping arrives.
+type HeartbeatChecker struct{}
+
+func (HeartbeatChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    // Monitors set up before the expected time was recorded count from
+    // the service's creation
+    expected := service.CreatedAt
+    if service.HeartbeatExpectedSince != nil {
+        expected = *service.HeartbeatExpectedSince
+    }
+
+    if service.LastHeartbeatAt == nil || service.LastHeartbeatAt.Before(expected) {
+        waiting := time.Since(expected)
+        if waiting > cfg.HeartbeatDeadline() {
+            return Result{
+                Message:   fmt.Sprintf("no heartbeat received in %s (expected every %ds)", waiting.Round(time.Second), cfg.PeriodSeconds),
+                Sustained: true,
+            }
+        }
+        return healthy("waiting for first heartbeat", 0)
+    }
+
+    since := time.Since(*service.LastHeartbeatAt)
+    if since > cfg.HeartbeatDeadline() {
+        return Result{
+            Message:   fmt.Sprintf("no heartbeat for %s (expected every %ds)", since.Round(time.Second), cfg.PeriodSeconds),
+            Sustained: true,
+        }
+    }
+
+    return healthy(fmt.Sprintf("last heartbeat %s ago", since.Round(time.Second)), 0)
+}
\ No newline at end of file
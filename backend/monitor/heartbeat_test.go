@@ -0,0 +1,49 @@
+package monitor
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "status-page-backend/models"
+)
+
+func TestHeartbeatChecker(t *testing.T) {
+    cfg := models.MonitorConfig{Enabled: true, Type: models.MonitorHeartbeat, PeriodSeconds: 300, GraceSeconds: 60}
+    ago := func(d time.Duration) *time.Time {
+        at := time.Now().Add(-d)
+        return &at
+    }
+
+    tests := []struct {
+        name     string
+        expected *time.Time
+        last     *time.Time
+        created  time.Duration
+        healthy  bool
+    }{
+        {"new token, no ping yet", ago(time.Minute), nil, time.Hour, true},
+        {"token issued long ago, never pinged", ago(time.Hour), nil, time.Hour, false},
+        {"no expected time, old service never pinged", nil, nil, time.Hour, false},
+        {"no expected time, new service", nil, nil, time.Minute, true},
+        {"recent ping", ago(time.Hour), ago(time.Minute), time.Hour, true},
+        {"ping overdue", ago(time.Hour), ago(10 * time.Minute), time.Hour, false},
+        // Re-enabling restarts the deadline even though the last ping is old
+        {"re-enabled after an old ping", ago(time.Minute), ago(24 * time.Hour), 48 * time.Hour, true},
+        {"re-enabled and still silent", ago(time.Hour), ago(24 * time.Hour), 48 * time.Hour, false},
+    }
+    for _, tt := range tests {
+        service := models.Service{
+            CreatedAt:              time.Now().Add(-tt.created),
+            HeartbeatExpectedSince: tt.expected,
+            LastHeartbeatAt:        tt.last,
+        }
+        result := HeartbeatChecker{}.Check(context.Background(), service, cfg)
+        if result.Healthy != tt.healthy {
+            t.Errorf("%s: result = %+v, want healthy %v", tt.name, result, tt.healthy)
+        }
+        if !result.Healthy && !result.Sustained {
+            t.Errorf("%s: a missed deadline should count as sustained", tt.name)
+        }
+    }
+}
\ No newline at end of file
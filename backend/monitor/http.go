@@ -0,0 +1,122 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/netip"
+    "strings"
+    "syscall"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// maxBodyBytes caps how much of a response is searched for BodyContains
+const maxBodyBytes = 1 << 20
+
+// HTTPChecker requests the service URL and checks the status code and,
+// optionally, that the body contains a substring
+type HTTPChecker struct {
+    // Client sends the request, nil means one that only reaches public addresses
+    Client *http.Client
+}
+
+var httpClient = &http.Client{
+    // Timeouts come from the per-check context. There is no proxy, so every
+    // connection, redirects included, goes through the address guard.
+    Transport: &http.Transport{
+        DialContext:       publicDialer.DialContext,
+        DisableKeepAlives: true,
+    },
+}
+
+// publicDialer is the default dialer for checkers that connect to the target
+var publicDialer = &net.Dialer{Control: publicAddressesOnly}
+
+// blockedNetworks are special-purpose ranges the net/netip predicates don't cover
+var blockedNetworks = []netip.Prefix{
+    netip.MustParsePrefix("0.0.0.0/8"),
+    netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// publicAddressesOnly refuses connections to loopback, link-local, private
+// and other internal addresses, so a monitor can't reach the cloud metadata
+// endpoint or services on our own network. It runs after DNS resolution.
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+    host, _, err := net.SplitHostPort(address)
+    if err != nil {
+        return err
+    }
+    ip, err := netip.ParseAddr(host)
+    if err != nil {
+        return err
+    }
+    ip = ip.Unmap()
+
+    if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+        ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+        return fmt.Errorf("address %s is not allowed", ip)
+    }
+    for _, prefix := range blockedNetworks {
+        if prefix.Contains(ip) {
+            return fmt.Errorf("address %s is not allowed", ip)
+        }
+    }
+    return nil
+}
+
+func (h HTTPChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    target, err := cfg.TargetFor(service.URL)
+    if err != nil {
+        return failed(err.Error(), 0)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, cfg.Method, target, nil)
+    if err != nil {
+        return failed(fmt.Sprintf("invalid request: %v", err), 0)
+    }
+    req.Header.Set("User-Agent", "status-page-monitor/1.0")
+
+    client := h.Client
+    if client == nil {
+        client = httpClient
+    }
+
+    start := time.Now()
+    resp, err := client.Do(req)
+    if err != nil {
+        return failed(fmt.Sprintf("request failed: %v", err), time.Since(start))
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+    latency := time.Since(start)
+    if err != nil {
+        return failed(fmt.Sprintf("reading body failed: %v", err), latency)
+    }
+
+    if !expectedStatus(resp.StatusCode, cfg.ExpectedStatusCodes) {
+        return failed(fmt.Sprintf("unexpected status code %d", resp.StatusCode), latency)
+    }
+    if cfg.BodyContains != "" && !strings.Contains(string(body), cfg.BodyContains) {
+        return failed(fmt.Sprintf("response body does not contain %q", cfg.BodyContains), latency)
+    }
+
+    return healthy(fmt.Sprintf("HTTP %d in %dms", resp.StatusCode, latency.Milliseconds()), latency)
+}
+
+// expectedStatus accepts any 2xx or 3xx when no codes are configured
+func expectedStatus(code int, expected []int) bool {
+    if len(expected) == 0 {
+        return code >= 200 && code < 400
+    }
+    for _, e := range expected {
+        if code == e {
+            return true
+        }
+    }
+    return false
+}
\ No newline at end of file
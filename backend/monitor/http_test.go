@@ -0,0 +1,57 @@
+package monitor
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "status-page-backend/models"
+)
+
+func TestPublicAddressesOnly(t *testing.T) {
+    tests := []struct {
+        address string
+        allowed bool
+    }{
+        {"127.0.0.1:80", false},
+        {"[::1]:443", false},
+        {"169.254.169.254:80", false},
+        {"[fe80::1]:80", false},
+        {"10.1.2.3:80", false},
+        {"172.16.0.1:80", false},
+        {"192.168.1.1:80", false},
+        {"[fd00::1]:80", false},
+        {"100.64.0.1:80", false},
+        {"0.0.0.0:80", false},
+        {"[::ffff:127.0.0.1]:80", false},
+        {"93.184.216.34:443", true},
+        {"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+    }
+    for _, tt := range tests {
+        err := publicAddressesOnly("tcp", tt.address, nil)
+        if (err == nil) != tt.allowed {
+            t.Errorf("%s: err = %v, want allowed %v", tt.address, err, tt.allowed)
+        }
+    }
+}
+
+func TestHTTPCheckerRefusesLoopback(t *testing.T) {
+    hits := 0
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        hits++
+    }))
+    defer server.Close()
+
+    cfg := models.MonitorConfig{Type: models.MonitorHTTP}
+    cfg.ApplyDefaults()
+    result := HTTPChecker{}.Check(context.Background(), models.Service{URL: server.URL}, cfg)
+
+    if result.Healthy || !strings.Contains(result.Message, "not allowed") {
+        t.Errorf("result = %+v, want a refused connection", result)
+    }
+    if hits != 0 {
+        t.Errorf("server received %d requests", hits)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/handlers"
+    "status-page-backend/models"
+)
+
+// followUpInterval is how often a still-open incident gets a "still down"
+// update when nothing else has changed
+const followUpInterval = 30 * time.Minute
+
+// trackIncident keeps the incident the monitor opened in step with the
+// checks. prev is the state before this result was evaluated.
+func (s *Scheduler) trackIncident(service models.Service, cfg models.MonitorConfig, prev models.MonitorState, state *models.MonitorState, result Result) {
+    now := time.Now()
+
+    var incident models.Incident
+    if state.IncidentID != nil {
+        err := database.GetCollection("incidents").FindOne(context.TODO(), bson.M{
+            "_id":             *state.IncidentID,
+            "organization_id": service.OrganizationID,
+            "deleted":         bson.M{"$ne": true},
+        }).Decode(&incident)
+
+        // Someone resolved or removed it, stop managing it
+        if err != nil || incident.Status == models.IncidentStatusResolved {
+            clearIncident(state)
+            return
+        }
+    }
+
+    wentDown := prev.AppliedStatus == "" && state.AppliedStatus != ""
+    down := !result.Healthy && state.AppliedStatus != ""
+
+    switch {
+    case wentDown && state.IncidentID == nil:
+        if !cfg.AutoIncident {
+            return
+        }
+        s.openIncident(service, state, result, now)
+
+    case wentDown:
+        // Failed again while we were watching the recovery
+        s.updateIncident(service, state, incident, models.IncidentStatusInvestigating, "Automated checks are failing again: "+result.Message, now)
+        state.RecoveredAt = nil
+
+    case down && state.IncidentID != nil:
+        escalated := prev.AppliedStatus != state.AppliedStatus
+        changed := prev.LastMessage != result.Message
+        stale := state.IncidentUpdatedAt == nil || now.Sub(*state.IncidentUpdatedAt) >= followUpInterval
+        if escalated || changed || stale {
+            s.updateIncident(service, state, incident, incident.Status, "Automated checks are still failing: "+result.Message, now)
+        }
+
+    case state.IncidentID != nil && state.AppliedStatus == "" && result.Healthy:
+        if incident.Status != models.IncidentStatusMonitoring {
+            s.updateIncident(service, state, incident, models.IncidentStatusMonitoring, "Automated checks are passing again, we are monitoring the service", now)
+            state.RecoveredAt = &now
+            return
+        }
+        if state.RecoveredAt == nil {
+            state.RecoveredAt = &now
+        }
+        if now.Sub(*state.RecoveredAt) >= cfg.AutoResolveAfter() {
+            if s.updateIncident(service, state, incident, models.IncidentStatusResolved, "This incident has been resolved", now) {
+                clearIncident(state)
+            }
+        }
+    }
+}
+
+func (s *Scheduler) openIncident(service models.Service, state *models.MonitorState, result Result, now time.Time) {
+    message := "Automated checks are failing: " + result.Message
+    incident := models.Incident{
+        OrganizationID:   service.OrganizationID,
+        Title:            service.Name + " is experiencing issues",
+        Description:      message,
+        Status:           models.IncidentStatusInvestigating,
+        Type:             "incident",
+        AffectedServices: []primitive.ObjectID{service.ID},
+        CreatedAt:        now,
+        UpdatedAt:        now,
+        CreatedBy:        models.ActorSystem,
+        Updates: []models.IncidentUpdate{{
+            ID:        primitive.NewObjectID(),
+            Status:    models.IncidentStatusInvestigating,
+            Message:   message,
+            CreatedBy: models.ActorSystem,
+            CreatedAt: now,
+        }},
+    }
+
+    incident, err := handlers.InsertIncident(s.hub, incident)
+    if err != nil {
+        log.Printf("Error opening incident for %s: %v", service.Name, err)
+        return
+    }
+
+    log.Printf("🚨 Opened incident for %s", service.Name)
+    state.IncidentID = &incident.ID
+    state.IncidentUpdatedAt = &now
+    state.RecoveredAt = nil
+}
+
+// updateIncident moves the incident on from the status it was read in. If
+// someone changed it meanwhile the update is skipped until the next check.
+func (s *Scheduler) updateIncident(service models.Service, state *models.MonitorState, incident models.Incident, status models.IncidentStatus, message string, now time.Time) bool {
+    _, err := handlers.AddIncidentUpdate(s.hub, service.OrganizationID, *state.IncidentID, incident.Status, status, message, models.ActorSystem)
+    if err == mongo.ErrNoDocuments {
+        log.Printf("Incident for %s changed during the check, not updated", service.Name)
+        return false
+    }
+    if err != nil {
+        log.Printf("Error updating incident for %s: %v", service.Name, err)
+        return false
+    }
+    state.IncidentUpdatedAt = &now
+    return true
+}
+
+func clearIncident(state *models.MonitorState) {
+    state.IncidentID = nil
+    state.IncidentUpdatedAt = nil
+    state.RecoveredAt = nil
+}
\ No newline at end of file
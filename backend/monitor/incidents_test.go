@@ -0,0 +1,162 @@
+package monitor
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// stubChecker returns whatever result currently points to
+type stubChecker struct {
+    result *Result
+}
+
+func (c stubChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    return *c.result
+}
+
+func TestTrackIncidentLifecycle(t *testing.T) {
+    setupTestDB(t)
+    var result Result
+    s := NewScheduler(nil, map[models.MonitorType]Checker{models.MonitorHTTP: stubChecker{&result}})
+    cfg := models.MonitorConfig{Enabled: true, Type: models.MonitorHTTP, AutoIncident: true, AutoResolveMinutes: 5}
+    cfg.ApplyDefaults()
+    cfg.FailureThreshold = 1
+    cfg.SuccessThreshold = 1
+
+    service := insertService(t, primitive.NewObjectID(), models.StatusOperational)
+    run := func(r Result) models.MonitorState {
+        t.Helper()
+        result = r
+        s.check(findService(t, service.ID), cfg)
+        stored := findService(t, service.ID)
+        if stored.MonitorState == nil {
+            t.Fatal("monitor state was not saved")
+        }
+        return *stored.MonitorState
+    }
+    incident := func(state models.MonitorState) models.Incident {
+        t.Helper()
+        if state.IncidentID == nil {
+            t.Fatal("no incident is tracked")
+        }
+        var incident models.Incident
+        if err := database.GetCollection("incidents").FindOne(context.TODO(), bson.M{"_id": *state.IncidentID}).Decode(&incident); err != nil {
+            t.Fatalf("find incident: %v", err)
+        }
+        return incident
+    }
+    expect := func(step string, state models.MonitorState, status models.IncidentStatus, updates int) models.Incident {
+        t.Helper()
+        got := incident(state)
+        if got.Status != status || len(got.Updates) != updates {
+            t.Fatalf("%s: incident is %s with %d updates, want %s with %d", step, got.Status, len(got.Updates), status, updates)
+        }
+        return got
+    }
+
+    // Going down opens an incident
+    state := run(Result{Message: "timeout"})
+    opened := expect("open", state, models.IncidentStatusInvestigating, 1)
+    if opened.CreatedBy != models.ActorSystem || len(opened.AffectedServices) != 1 || opened.AffectedServices[0] != service.ID {
+        t.Errorf("opened incident = %+v", opened)
+    }
+    if got := findService(t, service.ID).Status; got != cfg.FailureStatus {
+        t.Errorf("service status = %s, want %s", got, cfg.FailureStatus)
+    }
+
+    // The same failure right away adds nothing, a new one is reported
+    state = run(Result{Message: "timeout"})
+    expect("same failure", state, models.IncidentStatusInvestigating, 1)
+    state = run(Result{Message: "connection refused"})
+    if *state.IncidentID != opened.ID {
+        t.Fatal("a second incident was opened")
+    }
+    expect("new failure", state, models.IncidentStatusInvestigating, 2)
+
+    // Recovering moves it to monitoring and starts the resolve timer
+    state = run(Result{Healthy: true, Message: "HTTP 200"})
+    expect("recovered", state, models.IncidentStatusMonitoring, 3)
+    if state.RecoveredAt == nil {
+        t.Fatal("recovery time not recorded")
+    }
+    state = run(Result{Healthy: true, Message: "HTTP 200"})
+    expect("still recovering", state, models.IncidentStatusMonitoring, 3)
+
+    // Failing while monitoring reopens the investigation
+    state = run(Result{Message: "timeout"})
+    expect("failing again", state, models.IncidentStatusInvestigating, 4)
+    if state.RecoveredAt != nil {
+        t.Error("recovery time kept after failing again")
+    }
+
+    state = run(Result{Healthy: true, Message: "HTTP 200"})
+    expect("recovered again", state, models.IncidentStatusMonitoring, 5)
+
+    // Passing for AutoResolveMinutes resolves it
+    _, err := database.GetCollection("services").UpdateOne(context.TODO(),
+        bson.M{"_id": service.ID},
+        bson.M{"$set": bson.M{"monitor_state.recovered_at": time.Now().Add(-cfg.AutoResolveAfter() - time.Minute)}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    state = run(Result{Healthy: true, Message: "HTTP 200"})
+    if state.IncidentID != nil || state.RecoveredAt != nil {
+        t.Errorf("incident still tracked after resolving: %+v", state)
+    }
+    var resolved models.Incident
+    database.GetCollection("incidents").FindOne(context.TODO(), bson.M{"_id": opened.ID}).Decode(&resolved)
+    if resolved.Status != models.IncidentStatusResolved || len(resolved.Updates) != 6 {
+        t.Errorf("incident is %s with %d updates, want resolved with 6", resolved.Status, len(resolved.Updates))
+    }
+    if got := findService(t, service.ID).Status; got != models.StatusOperational {
+        t.Errorf("service status = %s, want operational", got)
+    }
+}
+
+// An incident someone resolved by hand is let go, the next outage opens a
+// new one
+func TestTrackIncidentLetsGoOfResolvedIncident(t *testing.T) {
+    setupTestDB(t)
+    var result Result
+    s := NewScheduler(nil, map[models.MonitorType]Checker{models.MonitorHTTP: stubChecker{&result}})
+    cfg := models.MonitorConfig{Enabled: true, Type: models.MonitorHTTP, AutoIncident: true}
+    cfg.ApplyDefaults()
+    cfg.FailureThreshold = 1
+    cfg.SuccessThreshold = 1
+
+    service := insertService(t, primitive.NewObjectID(), models.StatusOperational)
+    result = Result{Message: "timeout"}
+    s.check(findService(t, service.ID), cfg)
+    first := findService(t, service.ID).MonitorState.IncidentID
+    if first == nil {
+        t.Fatal("no incident opened")
+    }
+
+    _, err := database.GetCollection("incidents").UpdateOne(context.TODO(),
+        bson.M{"_id": *first},
+        bson.M{"$set": bson.M{"status": models.IncidentStatusResolved}},
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    result = Result{Healthy: true, Message: "HTTP 200"}
+    s.check(findService(t, service.ID), cfg)
+    if state := findService(t, service.ID).MonitorState; state.IncidentID != nil {
+        t.Fatalf("resolved incident still tracked: %+v", state)
+    }
+
+    result = Result{Message: "timeout"}
+    s.check(findService(t, service.ID), cfg)
+    second := findService(t, service.ID).MonitorState.IncidentID
+    if second == nil || *second == *first {
+        t.Errorf("second outage tracks %v, want a new incident", second)
+    }
+}
\ No newline at end of file
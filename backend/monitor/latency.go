@@ -0,0 +1,58 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// recordLatency stores the check's response time as a time series sample
+func recordLatency(service models.Service, result Result, now time.Time) {
+    if result.Latency <= 0 {
+        return
+    }
+
+    sample := models.LatencySample{
+        OrganizationID: service.OrganizationID,
+        ServiceID:      service.ID,
+        LatencyMs:      float64(result.Latency.Microseconds()) / 1000,
+        Healthy:        result.Healthy,
+        CreatedAt:      now,
+    }
+    if _, err := database.GetCollection("latency_samples").InsertOne(context.TODO(), sample); err != nil {
+        log.Printf("Error recording latency for %s: %v", service.Name, err)
+    }
+}
+
+// applyLatencyRule turns a healthy but slow result into a degraded one once
+// latency has stayed above the threshold for the configured window
+func applyLatencyRule(cfg models.MonitorConfig, state *models.MonitorState, result Result, now time.Time) Result {
+    if cfg.LatencyThresholdMs <= 0 || !result.Healthy {
+        state.SlowSince = nil
+        return result
+    }
+
+    if result.Latency <= cfg.LatencyThreshold() {
+        state.SlowSince = nil
+        return result
+    }
+
+    if state.SlowSince == nil {
+        state.SlowSince = &now
+    }
+    slowFor := now.Sub(*state.SlowSince)
+    if slowFor < cfg.LatencyWindow() {
+        return result
+    }
+
+    return Result{
+        Status:    models.StatusDegradedPerf,
+        Message:   fmt.Sprintf("latency %dms above %dms for %s", result.Latency.Milliseconds(), cfg.LatencyThresholdMs, slowFor.Round(time.Second)),
+        Latency:   result.Latency,
+        Sustained: true,
+    }
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// setupTestDB connects to a fresh database on MONGODB_TEST_URI and drops it
+// when the test ends. Tests are skipped without one.
+func setupTestDB(t *testing.T) {
+    t.Helper()
+    uri := os.Getenv("MONGODB_TEST_URI")
+    if uri == "" {
+        t.Skip("MONGODB_TEST_URI not set")
+    }
+    if err := database.ConnectDB(uri, fmt.Sprintf("statuspage_test_%d", time.Now().UnixNano())); err != nil {
+        t.Fatalf("connect: %v", err)
+    }
+    if err := database.EnsureIndexes(); err != nil {
+        t.Fatalf("indexes: %v", err)
+    }
+    db := database.DB
+    t.Cleanup(func() {
+        db.Drop(context.TODO())
+        db.Client().Disconnect(context.TODO())
+    })
+}
+
+// insertService stores a service of orgID in status
+func insertService(t *testing.T, orgID primitive.ObjectID, status models.ServiceStatus) models.Service {
+    t.Helper()
+    service := models.Service{
+        ID:             primitive.NewObjectID(),
+        OrganizationID: orgID,
+        Name:           "API",
+        Status:         status,
+        CreatedAt:      time.Now(),
+        UpdatedAt:      time.Now(),
+    }
+    if _, err := database.GetCollection("services").InsertOne(context.TODO(), service); err != nil {
+        t.Fatalf("insert service: %v", err)
+    }
+    return service
+}
+
+// findService loads a service by ID, failing the test if it is missing
+func findService(t *testing.T, id primitive.ObjectID) models.Service {
+    t.Helper()
+    var service models.Service
+    if err := database.GetCollection("services").FindOne(context.TODO(), map[string]interface{}{"_id": id}).Decode(&service); err != nil {
+        t.Fatalf("find service: %v", err)
+    }
+    return service
+}
\ No newline at end of file
@@ -0,0 +1,312 @@
+package monitor
+
+import (
+    "context"
+    "log"
+    "reflect"
+    "sync"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "status-page-backend/database"
+    "status-page-backend/handlers"
+    "status-page-backend/models"
+    "status-page-backend/websocket"
+)
+
+const (
+    tickInterval  = 5 * time.Second
+    maxConcurrent = 10
+)
+
+// Scheduler runs each service's monitor on its interval and moves the
+// service's status after enough consecutive failures or successes
+type Scheduler struct {
+    hub      *websocket.Hub
+    checkers map[models.MonitorType]Checker
+
+    mu      sync.Mutex
+    running map[primitive.ObjectID]bool
+    slots   chan struct{}
+}
+
+// NewScheduler runs monitors with the given checkers, usually DefaultCheckers
+func NewScheduler(hub *websocket.Hub, checkers map[models.MonitorType]Checker) *Scheduler {
+    return &Scheduler{
+        hub:      hub,
+        checkers: checkers,
+        running:  make(map[primitive.ObjectID]bool),
+        slots:    make(chan struct{}, maxConcurrent),
+    }
+}
+
+func (s *Scheduler) Run() {
+    ticker := time.NewTicker(tickInterval)
+    defer ticker.Stop()
+
+    for {
+        s.runDue(time.Now())
+        <-ticker.C
+    }
+}
+
+// runDue starts a check for every monitored service whose interval has passed
+func (s *Scheduler) runDue(now time.Time) {
+    cursor, err := database.GetCollection("services").Find(context.TODO(), bson.M{
+        "deleted":         bson.M{"$ne": true},
+        "monitor.enabled": true,
+    })
+    if err != nil {
+        log.Printf("Error finding monitored services: %v", err)
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var services []models.Service
+    if err := cursor.All(context.TODO(), &services); err != nil {
+        log.Printf("Error decoding monitored services: %v", err)
+        return
+    }
+
+    for _, service := range services {
+        cfg := *service.Monitor
+        cfg.ApplyDefaults()
+
+        if service.MonitorState != nil && now.Sub(service.MonitorState.LastCheckedAt) < cfg.Interval() {
+            continue
+        }
+        if !s.claim(service.ID) {
+            continue
+        }
+
+        go func(service models.Service, cfg models.MonitorConfig) {
+            s.slots <- struct{}{}
+            defer func() {
+                <-s.slots
+                s.release(service.ID)
+            }()
+            s.check(service, cfg)
+        }(service, cfg)
+    }
+}
+
+func (s *Scheduler) claim(id primitive.ObjectID) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.running[id] {
+        return false
+    }
+    s.running[id] = true
+    return true
+}
+
+func (s *Scheduler) release(id primitive.ObjectID) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    delete(s.running, id)
+}
+
+func (s *Scheduler) check(service models.Service, cfg models.MonitorConfig) {
+    checker, ok := s.checkers[cfg.Type]
+    if !ok {
+        log.Printf("❌ No checker for monitor type %q on %s", cfg.Type, service.Name)
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout())
+    result := checker.Check(ctx, service, cfg)
+    cancel()
+
+    now := time.Now()
+    recordLatency(service, result, now)
+
+    state := models.MonitorState{}
+    if service.MonitorState != nil {
+        state = *service.MonitorState
+    }
+
+    // Stay quiet during maintenance: no status changes, incidents or
+    // failure streaks carried past the window
+    quiet, err := handlers.InMaintenanceWindow(service.OrganizationID, service.ID)
+    if err != nil {
+        log.Printf("Error checking maintenance for %s: %v", service.Name, err)
+    }
+    if quiet {
+        state.LastCheckedAt = now
+        state.LastMessage = result.Message
+        state.Healthy = result.Healthy
+        state.ConsecutiveFailures = 0
+        state.ConsecutiveSuccesses = 0
+        state.SlowSince = nil
+    } else {
+        result = applyLatencyRule(cfg, &state, result, now)
+        prev := state
+        state = s.evaluate(service, cfg, state, result)
+        s.trackIncident(service, cfg, prev, &state, result)
+    }
+
+    saveState(service, state)
+}
+
+// saveState writes the fields of the monitor state that the check changed.
+// Heartbeats update the same document, so the write only goes through while
+// those fields still hold what was read. Otherwise the next check starts
+// again from the fresh state.
+func saveState(service models.Service, state models.MonitorState) {
+    filter := bson.M{"_id": service.ID}
+    var update bson.M
+    if service.MonitorState == nil {
+        filter["monitor_state"] = nil
+        update = bson.M{"$set": bson.M{"monitor_state": state}}
+    } else {
+        changes, err := stateChanges(*service.MonitorState, state)
+        if err != nil {
+            log.Printf("Error saving monitor state for %s: %v", service.Name, err)
+            return
+        }
+        if len(changes.set) == 0 && len(changes.unset) == 0 {
+            return
+        }
+        for key, value := range changes.read {
+            filter[key] = value
+        }
+        update = bson.M{}
+        if len(changes.set) > 0 {
+            update["$set"] = changes.set
+        }
+        if len(changes.unset) > 0 {
+            update["$unset"] = changes.unset
+        }
+    }
+
+    result, err := database.GetCollection("services").UpdateOne(context.TODO(), filter, update)
+    if err != nil {
+        log.Printf("Error saving monitor state for %s: %v", service.Name, err)
+        return
+    }
+    if result.MatchedCount == 0 {
+        log.Printf("Monitor state for %s changed during the check, not saved", service.Name)
+    }
+}
+
+// stateUpdate is the difference between two monitor states, keyed by the
+// dotted path of each field in the service document
+type stateUpdate struct {
+    set   bson.M
+    unset bson.M
+    // read matches the previous value of each changed field
+    read  bson.M
+}
+
+func stateChanges(before, after models.MonitorState) (stateUpdate, error) {
+    changes := stateUpdate{set: bson.M{}, unset: bson.M{}, read: bson.M{}}
+    old, err := stateFields(before)
+    if err != nil {
+        return changes, err
+    }
+    updated, err := stateFields(after)
+    if err != nil {
+        return changes, err
+    }
+
+    for key, value := range updated {
+        previous, ok := old[key]
+        if ok && reflect.DeepEqual(previous, value) {
+            continue
+        }
+        changes.set["monitor_state."+key] = value
+        if ok {
+            changes.read["monitor_state."+key] = previous
+        } else {
+            // Empty omitempty fields may be missing, or stored as ""
+            // by the heartbeat handler
+            changes.read["monitor_state."+key] = bson.M{"$in": bson.A{nil, ""}}
+        }
+    }
+    for key, previous := range old {
+        if _, ok := updated[key]; !ok {
+            changes.unset["monitor_state."+key] = ""
+            changes.read["monitor_state."+key] = previous
+        }
+    }
+    return changes, nil
+}
+
+// stateFields returns the state as it is stored, leaving out empty
+// omitempty fields
+func stateFields(state models.MonitorState) (bson.M, error) {
+    data, err := bson.Marshal(state)
+    if err != nil {
+        return nil, err
+    }
+    var fields bson.M
+    err = bson.Unmarshal(data, &fields)
+    return fields, err
+}
+
+// evaluate folds a check result into the monitor state and applies a status
+// change once a threshold is reached. The monitor never overrides a status
+// someone else set, and leaves services in maintenance alone.
+func (s *Scheduler) evaluate(service models.Service, cfg models.MonitorConfig, state models.MonitorState, result Result) models.MonitorState {
+    state.LastCheckedAt = time.Now()
+    state.LastMessage = result.Message
+    state.Healthy = result.Healthy
+
+    if result.Healthy {
+        state.ConsecutiveSuccesses++
+        state.ConsecutiveFailures = 0
+    } else {
+        state.ConsecutiveFailures++
+        state.ConsecutiveSuccesses = 0
+    }
+
+    if service.Status == models.StatusMaintenance {
+        return state
+    }
+
+    // Someone changed the status since the monitor set it, hand it back
+    if state.AppliedStatus != "" && service.Status != state.AppliedStatus {
+        state.AppliedStatus = ""
+    }
+
+    if !result.Healthy && (state.ConsecutiveFailures >= cfg.FailureThreshold || result.Sustained) {
+        target := result.Status
+        if target == "" {
+            target = cfg.FailureStatus
+        }
+
+        ownsStatus := service.Status == models.StatusOperational || state.AppliedStatus != ""
+        if ownsStatus && service.Status != target {
+            if s.apply(service, target, "Automated check failed: "+result.Message) {
+                state.AppliedStatus = target
+            }
+        }
+    }
+
+    if result.Healthy && state.ConsecutiveSuccesses >= cfg.SuccessThreshold && state.AppliedStatus != "" {
+        if s.apply(service, models.StatusOperational, "Automated check recovered: "+result.Message) {
+            state.AppliedStatus = ""
+        }
+    }
+
+    return state
+}
+
+// apply moves the service to status, but only while it still has the status
+// the check was evaluated against. A user, incident or maintenance window
+// that changed it during the check owns the status now.
+func (s *Scheduler) apply(service models.Service, status models.ServiceStatus, message string) bool {
+    _, err := handlers.ReplaceServiceStatus(s.hub, service.OrganizationID, service.ID, service.Status, status, message, models.ActorMonitor)
+    if err == mongo.ErrNoDocuments {
+        log.Printf("Status of %s changed during the check, leaving it", service.Name)
+        return false
+    }
+    if err != nil {
+        log.Printf("Error applying monitor status to %s: %v", service.Name, err)
+        return false
+    }
+    return true
+}
\ No newline at end of file
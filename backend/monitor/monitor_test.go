@@ -0,0 +1,119 @@
+package monitor
+
+import (
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/handlers"
+    "status-page-backend/models"
+)
+
+func TestStateChanges(t *testing.T) {
+    checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+    slowSince := checked.Add(-time.Minute)
+    incidentID := primitive.NewObjectID()
+
+    before := models.MonitorState{
+        LastCheckedAt:       checked,
+        LastMessage:         "HTTP 200 in 40ms",
+        Healthy:             true,
+        ConsecutiveFailures: 0,
+        SlowSince:           &slowSince,
+        IncidentID:          &incidentID,
+    }
+    after := before
+    after.LastCheckedAt = checked.Add(time.Minute)
+    after.LastMessage = "request failed"
+    after.Healthy = false
+    after.ConsecutiveFailures = 1
+    after.AppliedStatus = models.StatusMajorOutage
+    after.SlowSince = nil
+
+    changes, err := stateChanges(before, after)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    wantSet := []string{"last_checked_at", "last_message", "healthy", "consecutive_failures", "applied_status"}
+    if len(changes.set) != len(wantSet) {
+        t.Errorf("set = %v, want only %v", changes.set, wantSet)
+    }
+    for _, key := range wantSet {
+        if _, ok := changes.set["monitor_state."+key]; !ok {
+            t.Errorf("%s not set", key)
+        }
+    }
+    for _, key := range []string{"incident_id", "consecutive_successes"} {
+        if _, ok := changes.set["monitor_state."+key]; ok {
+            t.Errorf("unchanged %s was set", key)
+        }
+    }
+    if len(changes.unset) != 1 || changes.unset["monitor_state.slow_since"] == nil {
+        t.Errorf("unset = %v, want slow_since", changes.unset)
+    }
+
+    // The write is guarded on what was read
+    if changes.read["monitor_state.healthy"] != true {
+        t.Errorf("healthy guard = %v, want true", changes.read["monitor_state.healthy"])
+    }
+    if changes.read["monitor_state.last_message"] != "HTTP 200 in 40ms" {
+        t.Errorf("last_message guard = %v", changes.read["monitor_state.last_message"])
+    }
+    if guard, _ := changes.read["monitor_state.applied_status"].(bson.M); guard == nil {
+        t.Errorf("applied_status guard = %v, want a match on unset", changes.read["monitor_state.applied_status"])
+    }
+    if _, ok := changes.read["monitor_state.incident_id"]; ok {
+        t.Errorf("unchanged incident_id is guarded")
+    }
+    if len(changes.read) != len(wantSet)+1 {
+        t.Errorf("read = %v, want a guard per changed field", changes.read)
+    }
+
+    if unchanged, _ := stateChanges(before, before); len(unchanged.set)+len(unchanged.unset) != 0 {
+        t.Errorf("identical states produced changes: %+v", unchanged)
+    }
+}
+
+// A status someone set while the check ran is left alone, both when the
+// check fails and when it recovers
+func TestEvaluateKeepsStatusChangedDuringCheck(t *testing.T) {
+    setupTestDB(t)
+    s := NewScheduler(nil, nil)
+    cfg := models.MonitorConfig{Enabled: true, Type: models.MonitorHTTP}
+    cfg.ApplyDefaults()
+    cfg.FailureThreshold = 1
+    cfg.SuccessThreshold = 1
+
+    // Read as operational, then a user moved it to maintenance
+    service := insertService(t, primitive.NewObjectID(), models.StatusOperational)
+    if _, err := handlers.ApplyServiceStatus(nil, service.OrganizationID, service.ID, models.StatusMaintenance, "", "test-user"); err != nil {
+        t.Fatal(err)
+    }
+    state := s.evaluate(service, cfg, models.MonitorState{}, Result{Message: "timeout"})
+    if state.AppliedStatus != "" {
+        t.Errorf("applied status = %s, want none", state.AppliedStatus)
+    }
+    if got := findService(t, service.ID).Status; got != models.StatusMaintenance {
+        t.Errorf("status = %s, want the user's maintenance", got)
+    }
+
+    // Read as the monitor's outage, then an incident moved it on
+    service = insertService(t, primitive.NewObjectID(), models.StatusMajorOutage)
+    if _, err := handlers.ApplyServiceStatus(nil, service.OrganizationID, service.ID, models.StatusPartialOutage, "", "test-user"); err != nil {
+        t.Fatal(err)
+    }
+    s.evaluate(service, cfg, models.MonitorState{AppliedStatus: models.StatusMajorOutage}, Result{Healthy: true, Message: "HTTP 200"})
+    if got := findService(t, service.ID).Status; got != models.StatusPartialOutage {
+        t.Errorf("status = %s after recovery, want the incident's partial_outage", got)
+    }
+
+    // Nobody interfered, the monitor applies its status
+    service = insertService(t, primitive.NewObjectID(), models.StatusOperational)
+    state = s.evaluate(service, cfg, models.MonitorState{}, Result{Message: "timeout"})
+    if state.AppliedStatus != cfg.FailureStatus || findService(t, service.ID).Status != cfg.FailureStatus {
+        t.Errorf("unguarded failure: applied %s, status %s, want %s", state.AppliedStatus, findService(t, service.ID).Status, cfg.FailureStatus)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// TCPChecker opens a raw TCP connection to host:port
+type TCPChecker struct {
+    // Dialer connects to the target, nil means one that only reaches public addresses
+    Dialer *net.Dialer
+}
+
+func (t TCPChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    target, err := cfg.TargetFor(service.URL)
+    if err != nil {
+        return failed(err.Error(), 0)
+    }
+
+    dialer := t.Dialer
+    if dialer == nil {
+        dialer = publicDialer
+    }
+
+    start := time.Now()
+    conn, err := dialer.DialContext(ctx, "tcp", target)
+    latency := time.Since(start)
+    if err != nil {
+        return failed(fmt.Sprintf("connect to %s failed: %v", target, err), latency)
+    }
+    conn.Close()
+
+    return healthy(fmt.Sprintf("connected to %s in %dms", target, latency.Milliseconds()), latency)
+}
\ No newline at end of file
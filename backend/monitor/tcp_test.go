@@ -0,0 +1,56 @@
+package monitor
+
+import (
+    "context"
+    "net"
+    "testing"
+
+    "status-page-backend/models"
+)
+
+// localDialer reaches the loopback listeners the tests start
+var localDialer = &net.Dialer{}
+
+func TestTCPChecker(t *testing.T) {
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    go func() {
+        for {
+            conn, err := listener.Accept()
+            if err != nil {
+                return
+            }
+            conn.Close()
+        }
+    }()
+    open := listener.Addr().String()
+
+    // A port nothing listens on once the listener is gone
+    closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    closed := closedListener.Addr().String()
+    closedListener.Close()
+    defer listener.Close()
+
+    tests := []struct {
+        name    string
+        checker TCPChecker
+        target  string
+        healthy bool
+    }{
+        {"listening port", TCPChecker{Dialer: localDialer}, open, true},
+        {"closed port", TCPChecker{Dialer: localDialer}, closed, false},
+        {"default dialer refuses loopback", TCPChecker{}, open, false},
+    }
+    for _, tt := range tests {
+        cfg := models.MonitorConfig{Type: models.MonitorTCP, Target: tt.target}
+        result := tt.checker.Check(context.Background(), models.Service{}, cfg)
+        if result.Healthy != tt.healthy {
+            t.Errorf("%s: result = %+v, want healthy %v", tt.name, result, tt.healthy)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "net"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// TLSChecker completes a TLS handshake and inspects the leaf certificate.
+// A certificate close to expiry is reported as degraded_performance.
+type TLSChecker struct {
+    // RootCAs verifies the chain, nil means the system roots
+    RootCAs *x509.CertPool
+    // Dialer connects to the target, nil means one that only reaches public addresses
+    Dialer *net.Dialer
+}
+
+func (t TLSChecker) Check(ctx context.Context, service models.Service, cfg models.MonitorConfig) Result {
+    target, err := cfg.TargetFor(service.URL)
+    if err != nil {
+        return failed(err.Error(), 0)
+    }
+    host, _, _ := net.SplitHostPort(target)
+
+    dialer := tls.Dialer{NetDialer: t.Dialer, Config: &tls.Config{ServerName: host, RootCAs: t.RootCAs}}
+    if dialer.NetDialer == nil {
+        dialer.NetDialer = publicDialer
+    }
+    start := time.Now()
+    conn, err := dialer.DialContext(ctx, "tcp", target)
+    latency := time.Since(start)
+    if err != nil {
+        return failed(fmt.Sprintf("TLS handshake with %s failed: %v", target, err), latency)
+    }
+    defer conn.Close()
+
+    certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+    if len(certs) == 0 {
+        return failed(fmt.Sprintf("%s presented no certificate", target), latency)
+    }
+
+    remaining := time.Until(certs[0].NotAfter)
+    days := int(remaining.Hours() / 24)
+    if remaining < time.Duration(cfg.CertExpiryWarningDays)*24*time.Hour {
+        return Result{
+            Status:  models.StatusDegradedPerf,
+            Message: fmt.Sprintf("certificate for %s expires in %d days (%s)", host, days, certs[0].NotAfter.Format(time.DateOnly)),
+            Latency: latency,
+        }
+    }
+
+    return healthy(fmt.Sprintf("certificate for %s valid for %d more days", host, days), latency)
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+package monitor
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "math/big"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "status-page-backend/models"
+)
+
+// tlsServer serves a self-signed certificate for 127.0.0.1 that expires
+// after validFor, and returns the server and a pool that trusts it
+func tlsServer(t *testing.T, validFor time.Duration) (*httptest.Server, *x509.CertPool) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    template := &x509.Certificate{
+        SerialNumber:          big.NewInt(1),
+        Subject:               pkix.Name{CommonName: "127.0.0.1"},
+        IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(validFor),
+        KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+        ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+        IsCA:                  true,
+        BasicConstraintsValid: true,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+    server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+    server.StartTLS()
+    t.Cleanup(server.Close)
+
+    pool := x509.NewCertPool()
+    pool.AddCert(cert)
+    return server, pool
+}
+
+func TestTLSChecker(t *testing.T) {
+    valid, validRoots := tlsServer(t, 90*24*time.Hour)
+    expiring, expiringRoots := tlsServer(t, 5*24*time.Hour)
+
+    tests := []struct {
+        name    string
+        server  *httptest.Server
+        checker TLSChecker
+        healthy bool
+        status  models.ServiceStatus
+        message string
+    }{
+        {"valid certificate", valid, TLSChecker{RootCAs: validRoots, Dialer: localDialer}, true, "", "valid for 89 more days"},
+        {"expiring certificate", expiring, TLSChecker{RootCAs: expiringRoots, Dialer: localDialer}, false, models.StatusDegradedPerf, "expires in 4 days"},
+        {"untrusted certificate", valid, TLSChecker{Dialer: localDialer}, false, "", "handshake"},
+        {"default dialer refuses loopback", valid, TLSChecker{RootCAs: validRoots}, false, "", "not allowed"},
+    }
+    for _, tt := range tests {
+        cfg := models.MonitorConfig{Type: models.MonitorTLS, Target: tt.server.Listener.Addr().String()}
+        cfg.ApplyDefaults()
+        result := tt.checker.Check(context.Background(), models.Service{}, cfg)
+
+        if result.Healthy != tt.healthy || result.Status != tt.status {
+            t.Errorf("%s: result = %+v, want healthy %v status %q", tt.name, result, tt.healthy, tt.status)
+        }
+        if !strings.Contains(result.Message, tt.message) {
+            t.Errorf("%s: message = %q, want it to mention %q", tt.name, result.Message, tt.message)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,189 @@
+package rollup
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+    "status-page-backend/uptime"
+)
+
+// MaxDays is how far back rollups are kept up to date
+const MaxDays = 90
+
+const day = 24 * time.Hour
+
+// Roller periodically rolls service status transitions up into one
+// daily_status document per service per UTC day
+type Roller struct {
+    interval time.Duration
+}
+
+func NewRoller(interval time.Duration) *Roller {
+    return &Roller{interval: interval}
+}
+
+func (r *Roller) Run() {
+    ticker := time.NewTicker(r.interval)
+    defer ticker.Stop()
+
+    for {
+        r.RollupAll(time.Now())
+        <-ticker.C
+    }
+}
+
+// RollupAll refreshes rollups for every live service
+func (r *Roller) RollupAll(now time.Time) {
+    cursor, err := database.GetCollection("services").Find(context.TODO(), bson.M{
+        "deleted": bson.M{"$ne": true},
+    })
+    if err != nil {
+        log.Printf("Error finding services for rollup: %v", err)
+        return
+    }
+    defer cursor.Close(context.TODO())
+
+    var services []models.Service
+    if err := cursor.All(context.TODO(), &services); err != nil {
+        log.Printf("Error decoding services for rollup: %v", err)
+        return
+    }
+
+    for _, service := range services {
+        if err := RollupService(service, now); err != nil {
+            log.Printf("Error rolling up %s: %v", service.Name, err)
+        }
+    }
+}
+
+// RollupService recomputes the service's rollups from its latest stored day,
+// which may have been partial, through today
+func RollupService(service models.Service, now time.Time) error {
+    collection := database.GetCollection("daily_status")
+    today := now.UTC().Truncate(day)
+
+    start := today.Add(-(MaxDays - 1) * day)
+    if created := service.CreatedAt.UTC().Truncate(day); created.After(start) {
+        start = created
+    }
+
+    var latest models.DailyStatus
+    err := collection.FindOne(
+        context.TODO(),
+        bson.M{"service_id": service.ID},
+        options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+    ).Decode(&latest)
+    if err != nil && err != mongo.ErrNoDocuments {
+        return err
+    }
+    if err == nil && latest.Date.After(start) {
+        start = latest.Date
+    }
+
+    from := start
+    if service.CreatedAt.After(from) {
+        from = service.CreatedAt
+    }
+
+    initial, changes, err := uptime.ChangesBetween(service, from, now)
+    if err != nil {
+        return err
+    }
+
+    incidents, err := incidentsBetween(service, start, now)
+    if err != nil {
+        return err
+    }
+
+    for date := start; !date.After(today); date = date.Add(day) {
+        dayFrom, dayTo := date, date.Add(day)
+        if service.CreatedAt.After(dayFrom) {
+            dayFrom = service.CreatedAt
+        }
+        if now.Before(dayTo) {
+            dayTo = now
+        }
+
+        result := uptime.Calculate(initial, changes, dayFrom, dayTo, uptime.DefaultWeights)
+
+        minutes := make(map[models.ServiceStatus]float64, len(result.Durations))
+        worst := models.StatusOperational
+        for status, seconds := range result.Durations {
+            if seconds <= 0 {
+                continue
+            }
+            minutes[status] = seconds / 60
+            if status.Worse(worst) {
+                worst = status
+            }
+        }
+
+        _, err := collection.UpdateOne(
+            context.TODO(),
+            bson.M{"service_id": service.ID, "date": date},
+            bson.M{"$set": bson.M{
+                "organization_id":   service.OrganizationID,
+                "worst_status":      worst,
+                "minutes":           minutes,
+                "uptime_percentage": result.Percentage,
+                "incident_ids":      incidentIDsOn(incidents, date, date.Add(day)),
+                "updated_at":        now,
+            }},
+            options.Update().SetUpsert(true),
+        )
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// incidentsBetween returns incidents affecting the service that were open at
+// some point in [from, to)
+func incidentsBetween(service models.Service, from, to time.Time) ([]models.Incident, error) {
+    cursor, err := database.GetCollection("incidents").Find(context.TODO(), bson.M{
+        "organization_id":   service.OrganizationID,
+        "affected_services": service.ID,
+        "deleted":           bson.M{"$ne": true},
+        "created_at":        bson.M{"$lt": to},
+        "$or": []bson.M{
+            {"resolved_at": bson.M{"$gte": from}},
+            {"resolved_at": nil, "status": bson.M{"$ne": models.IncidentStatusResolved}},
+            // Resolved without a resolved_at, see Incident.ClosedAt
+            {"resolved_at": nil, "updated_at": bson.M{"$gte": from}},
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    incidents := make([]models.Incident, 0)
+    if err := cursor.All(context.TODO(), &incidents); err != nil {
+        return nil, err
+    }
+    return incidents, nil
+}
+
+func incidentIDsOn(incidents []models.Incident, from, to time.Time) []primitive.ObjectID {
+    ids := make([]primitive.ObjectID, 0)
+    for _, incident := range incidents {
+        if !incident.CreatedAt.Before(to) {
+            continue
+        }
+        if closed := incident.ClosedAt(); closed != nil && closed.Before(from) {
+            continue
+        }
+        ids = append(ids, incident.ID)
+    }
+    return ids
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package rollup
+
+import (
+    "testing"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/primitive"
+
+    "status-page-backend/models"
+)
+
+func TestIncidentIDsOn(t *testing.T) {
+    day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+    day2 := day1.Add(day)
+    resolvedOnDay1 := day1.Add(20 * time.Hour)
+
+    open := models.Incident{
+        ID:        primitive.NewObjectID(),
+        Status:    models.IncidentStatusInvestigating,
+        CreatedAt: day1.Add(time.Hour),
+        UpdatedAt: day1.Add(2 * time.Hour),
+    }
+    resolved := models.Incident{
+        ID:         primitive.NewObjectID(),
+        Status:     models.IncidentStatusResolved,
+        CreatedAt:  day1.Add(time.Hour),
+        UpdatedAt:  resolvedOnDay1,
+        ResolvedAt: &resolvedOnDay1,
+    }
+    // Resolved without resolved_at, closed at its last update
+    legacy := models.Incident{
+        ID:        primitive.NewObjectID(),
+        Status:    models.IncidentStatusResolved,
+        CreatedAt: day1.Add(time.Hour),
+        UpdatedAt: resolvedOnDay1,
+    }
+    incidents := []models.Incident{open, resolved, legacy}
+
+    tests := []struct {
+        name string
+        from time.Time
+        want []primitive.ObjectID
+    }{
+        {"day the incidents opened", day1, []primitive.ObjectID{open.ID, resolved.ID, legacy.ID}},
+        {"day after", day2, []primitive.ObjectID{open.ID}},
+    }
+    for _, tt := range tests {
+        got := incidentIDsOn(incidents, tt.from, tt.from.Add(day))
+        if len(got) != len(tt.want) {
+            t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+            continue
+        }
+        for i := range got {
+            if got[i] != tt.want[i] {
+                t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+                break
+            }
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,57 @@
+package uptime
+
+import (
+    "context"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "status-page-backend/database"
+    "status-page-backend/models"
+)
+
+// ChangesBetween returns the status a service had at from and all of its
+// transitions in [from, to), oldest first
+func ChangesBetween(service models.Service, from, to time.Time) (models.ServiceStatus, []models.StatusChange, error) {
+    collection := database.GetCollection("status_history")
+
+    var last models.StatusChange
+    err := collection.FindOne(
+        context.TODO(),
+        bson.M{"service_id": service.ID, "created_at": bson.M{"$lte": from}},
+        options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+    ).Decode(&last)
+    if err != nil && err != mongo.ErrNoDocuments {
+        return "", nil, err
+    }
+
+    cursor, err := collection.Find(
+        context.TODO(),
+        bson.M{"service_id": service.ID, "created_at": bson.M{"$gt": from, "$lt": to}},
+        options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}),
+    )
+    if err != nil {
+        return "", nil, err
+    }
+    defer cursor.Close(context.TODO())
+
+    changes := make([]models.StatusChange, 0)
+    if err := cursor.All(context.TODO(), &changes); err != nil {
+        return "", nil, err
+    }
+
+    // Without a transition before the window, fall back to what the first one
+    // in the window changed from, or the current status if nothing changed.
+    // Services created before history was recorded land here.
+    initial := last.NewStatus
+    if initial == "" && len(changes) > 0 {
+        initial = changes[0].OldStatus
+    }
+    if initial == "" {
+        initial = service.Status
+    }
+
+    return initial, changes, nil
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package uptime
+
+import (
+    "time"
+
+    "status-page-backend/models"
+)
+
+// Weights is the fraction of time in a status that counts as downtime.
+// Operational time never counts and maintenance is excluded entirely.
+type Weights struct {
+    MajorOutage         float64
+    PartialOutage       float64
+    DegradedPerformance float64
+}
+
+var DefaultWeights = Weights{
+    MajorOutage:         1.0,
+    PartialOutage:       0.3,
+    DegradedPerformance: 0.1,
+}
+
+func (w Weights) downtime(s models.ServiceStatus) float64 {
+    switch s {
+    case models.StatusMajorOutage:
+        return w.MajorOutage
+    case models.StatusPartialOutage:
+        return w.PartialOutage
+    case models.StatusDegradedPerf:
+        return w.DegradedPerformance
+    }
+    return 0
+}
+
+// Windows are the standard uptime periods exposed by the API
+var Windows = map[string]time.Duration{
+    "day":   24 * time.Hour,
+    "week":  7 * 24 * time.Hour,
+    "month": 30 * 24 * time.Hour,
+    "90d":   90 * 24 * time.Hour,
+}
+
+type Result struct {
+    From       time.Time                      `json:"from"`
+    To         time.Time                      `json:"to"`
+    Percentage float64                        `json:"uptime_percentage"`
+    Downtime   float64                        `json:"downtime_seconds"`
+    Durations  map[models.ServiceStatus]float64 `json:"durations"`
+}
+
+// Calculate computes uptime over [from, to). initial is the status the
+// service had before the first change, changes must be sorted by CreatedAt.
+// Changes before from only serve to establish the starting status.
+func Calculate(initial models.ServiceStatus, changes []models.StatusChange, from, to time.Time, w Weights) Result {
+    result := Result{
+        From:       from,
+        To:         to,
+        Percentage: 100,
+        Durations:  make(map[models.ServiceStatus]float64),
+    }
+    if !to.After(from) {
+        return result
+    }
+
+    status := initial
+    cursor := from
+    for _, change := range changes {
+        if !change.CreatedAt.After(from) {
+            status = change.NewStatus
+            continue
+        }
+        if !change.CreatedAt.Before(to) {
+            break
+        }
+        result.Durations[status] += change.CreatedAt.Sub(cursor).Seconds()
+        status = change.NewStatus
+        cursor = change.CreatedAt
+    }
+    result.Durations[status] += to.Sub(cursor).Seconds()
+
+    result.Percentage, result.Downtime = Percentage(result.Durations, w)
+    return result
+}
+
+// Percentage turns seconds spent in each status into an uptime percentage and
+// weighted downtime in seconds. Maintenance time is left out.
+func Percentage(durations map[models.ServiceStatus]float64, w Weights) (float64, float64) {
+    var counted, downtime float64
+    for s, seconds := range durations {
+        if s == models.StatusMaintenance {
+            continue
+        }
+        counted += seconds
+        downtime += seconds * w.downtime(s)
+    }
+
+    if counted == 0 {
+        return 100, 0
+    }
+    return 100 * (1 - downtime/counted), downtime
+}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package uptime
+
+import (
+    "math"
+    "testing"
+    "time"
+
+    "status-page-backend/models"
+)
+
+func TestCalculate(t *testing.T) {
+    from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    to := from.Add(10 * time.Hour)
+    at := func(h float64, s models.ServiceStatus) models.StatusChange {
+        return models.StatusChange{NewStatus: s, CreatedAt: from.Add(time.Duration(h * float64(time.Hour)))}
+    }
+    hours := func(h float64) float64 { return h * 3600 }
+
+    tests := []struct {
+        name     string
+        initial  models.ServiceStatus
+        changes  []models.StatusChange
+        from, to time.Time
+        want     float64
+        downtime float64
+    }{
+        {"no changes", models.StatusOperational, nil, from, to, 100, 0},
+        {"down the whole window", models.StatusMajorOutage, nil, from, to, 0, hours(10)},
+        {"half major outage", models.StatusOperational,
+            []models.StatusChange{at(5, models.StatusMajorOutage)}, from, to, 50, hours(5)},
+        {"partial outage is weighted", models.StatusOperational,
+            []models.StatusChange{at(5, models.StatusPartialOutage)}, from, to, 85, hours(1.5)},
+        {"degraded is weighted", models.StatusOperational,
+            []models.StatusChange{at(5, models.StatusDegradedPerf)}, from, to, 95, hours(0.5)},
+        {"maintenance is excluded", models.StatusOperational,
+            []models.StatusChange{at(2, models.StatusMaintenance), at(6, models.StatusMajorOutage), at(9, models.StatusOperational)},
+            from, to, 50, hours(3)},
+        {"only maintenance", models.StatusMaintenance, nil, from, to, 100, 0},
+        {"changes before from set the starting status", models.StatusOperational,
+            []models.StatusChange{at(-5, models.StatusMajorOutage), at(-1, models.StatusPartialOutage), at(5, models.StatusOperational)},
+            from, to, 85, hours(1.5)},
+        {"change at from sets the starting status", models.StatusOperational,
+            []models.StatusChange{at(0, models.StatusMajorOutage), at(5, models.StatusOperational)}, from, to, 50, hours(5)},
+        {"changes at or after to are ignored", models.StatusOperational,
+            []models.StatusChange{at(10, models.StatusMajorOutage), at(12, models.StatusOperational)}, from, to, 100, 0},
+        {"empty window", models.StatusMajorOutage,
+            []models.StatusChange{at(-1, models.StatusMajorOutage)}, from, from, 100, 0},
+        {"inverted window", models.StatusMajorOutage, nil, to, from, 100, 0},
+    }
+
+    for _, tt := range tests {
+        got := Calculate(tt.initial, tt.changes, tt.from, tt.to, DefaultWeights)
+        if math.Abs(got.Percentage-tt.want) > 1e-9 {
+            t.Errorf("%s: percentage = %v, want %v", tt.name, got.Percentage, tt.want)
+        }
+        if math.Abs(got.Downtime-tt.downtime) > 1e-6 {
+            t.Errorf("%s: downtime = %v, want %v", tt.name, got.Downtime, tt.downtime)
+        }
+        var total float64
+        for _, seconds := range got.Durations {
+            total += seconds
+        }
+        if want := math.Max(tt.to.Sub(tt.from).Seconds(), 0); math.Abs(total-want) > 1e-6 {
+            t.Errorf("%s: durations add up to %v, want %v", tt.name, total, want)
+        }
+    }
+}
+
+func TestPercentage(t *testing.T) {
+    custom := Weights{MajorOutage: 1, PartialOutage: 0.5, DegradedPerformance: 0}
+
+    tests := []struct {
+        name      string
+        durations map[models.ServiceStatus]float64
+        w         Weights
+        want      float64
+        downtime  float64
+    }{
+        {"empty", map[models.ServiceStatus]float64{}, DefaultWeights, 100, 0},
+        {"nil", nil, DefaultWeights, 100, 0},
+        {"only maintenance", map[models.ServiceStatus]float64{models.StatusMaintenance: 100}, DefaultWeights, 100, 0},
+        {"all operational", map[models.ServiceStatus]float64{models.StatusOperational: 100}, DefaultWeights, 100, 0},
+        {"mixed", map[models.ServiceStatus]float64{
+            models.StatusOperational:   60,
+            models.StatusMajorOutage:   10,
+            models.StatusPartialOutage: 20,
+            models.StatusDegradedPerf:  10,
+            models.StatusMaintenance:   1000,
+        }, DefaultWeights, 83, 17},
+        {"custom weights", map[models.ServiceStatus]float64{
+            models.StatusOperational:   50,
+            models.StatusPartialOutage: 40,
+            models.StatusDegradedPerf:  10,
+        }, custom, 80, 20},
+        {"unknown status counts as up", map[models.ServiceStatus]float64{
+            models.ServiceStatus("bogus"): 50,
+            models.StatusMajorOutage:      50,
+        }, DefaultWeights, 50, 50},
+    }
+
+    for _, tt := range tests {
+        got, downtime := Percentage(tt.durations, tt.w)
+        if math.Abs(got-tt.want) > 1e-9 || math.Abs(downtime-tt.downtime) > 1e-9 {
+            t.Errorf("%s: Percentage = %v, %v, want %v, %v", tt.name, got, downtime, tt.want, tt.downtime)
+        }
+    }
+}
\ No newline at end of file